@@ -3,13 +3,20 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"hab/internal"
 )
 
-var dateFlag string
+var (
+	dateFlag    string
+	addRange    string
+	addWeekdays string
+	addForce    bool
+	addBy       string
+)
 
 // addEntry is the shared function for adding entries
 func addEntry(habitKey, date string) {
@@ -26,30 +33,52 @@ func addEntry(habitKey, date string) {
 		os.Exit(1)
 	}
 
-	// Use provided date or default to today
-	entryDate := date
-	if entryDate == "" {
-		entryDate = time.Now().Format("2006-01-02")
-	}
-
-	// Add the entry
-	if err := hm.AddEntry(habitKey, entryDate); err != nil {
-		fmt.Printf("Error adding entry: %v\n", err)
+	// Resolve the date, range or relative date; defaults to today
+	now := time.Now()
+	dates, err := resolveEntryDates(date, addRange, addWeekdays)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	if len(dates) == 0 {
+		fmt.Println("No dates in range match the given weekdays")
+		return
+	}
 
 	// Get habit info for confirmation
 	activity, _ := hm.GetActivity(habitKey)
+
+	// Preview backfills and confirm unless --force is used
+	if len(dates) > 1 && !addForce {
+		fmt.Printf("Adding %d entries for '%s':\n", len(dates), activity.Name)
+		printDatePreview(dates)
+		if !confirm("Continue?") {
+			fmt.Println("Operation cancelled")
+			return
+		}
+	}
+
+	// Add the entries with a single save
+	if err := hm.AddEntries(habitKey, dates, strings.TrimSpace(addBy)); err != nil {
+		fmt.Printf("Error adding entry: %v\n", err)
+		os.Exit(1)
+	}
 	
-	if entryDate == time.Now().Format("2006-01-02") {
+	switch {
+	case len(dates) > 1:
+		fmt.Printf("✓ Added %d entries for '%s' from %s to %s\n", len(dates), activity.Name, dates[0], dates[len(dates)-1])
+	case dates[0] == now.Format(internal.DateFormat):
 		fmt.Printf("✓ Added entry for '%s' today\n", activity.Name)
-	} else {
-		fmt.Printf("✓ Added entry for '%s' on %s\n", activity.Name, entryDate)
+	default:
+		fmt.Printf("✓ Added entry for '%s' on %s\n", activity.Name, dates[0])
 	}
 
-	// Show current streak if available
+	// Show current streak if available; for a habit to avoid the entry was a
+	// slip, which may have ended it
 	if stats, err := hm.GetStats(habitKey); err == nil {
-		if streak, ok := stats["current_streak"].(int); ok && streak > 0 {
+		if streak, ok := stats["current_streak"].(int); ok && activity.IsAvoid() {
+			fmt.Printf("Current streak: %d days without a slip\n", streak)
+		} else if ok && streak > 0 {
 			fmt.Printf("Current streak: %d days 🔥\n", streak)
 		}
 	}
@@ -61,10 +90,24 @@ var addCmd = &cobra.Command{
 	Short: "Add an entry for a habit",
 	Long: `Add an entry for a habit. If no date is specified, today's date is used.
 
+Dates can be YYYY-MM-DD, today, yesterday, an offset such as 3d, 2w or
+"3 days ago", a weekday (mon is the most recent Monday, "last mon" the one
+before today) or an ISO week date such as 2025-W03-2. Join two dates with ..
+(or use --range) to add an entry for every day in between, optionally only
+on --weekdays. Backfills are previewed and need confirmation unless --force
+is used.
+
 Examples:
   hab add exercise           # Add entry for today
-  hab add exercise 2025-01-15  # Add entry for specific date`,
-	Args: cobra.RangeArgs(1, 2),
+  hab add exercise yesterday   # Add entry for yesterday
+  hab add exercise "last fri"  # Add entry for last Friday
+  hab add exercise 3d          # Add entry for three days ago
+  hab add exercise 2025-01-15  # Add entry for specific date
+  hab add exercise 2025-01-01..2025-01-07  # Backfill a week
+  hab add exercise --range 2025-03-01..2025-03-31 --weekdays mon,wed,fri
+  hab add watering --by alice  # Record who did it on a shared habit`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeHabitKeyThenDate,
 	Run: func(cmd *cobra.Command, args []string) {
 		habitKey := args[0]
 		date := ""
@@ -80,5 +123,10 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(addCmd)
-	addCmd.Flags().StringVarP(&dateFlag, "date", "d", "", "Date to add entry for (YYYY-MM-DD)")
+	addCmd.Flags().StringVarP(&dateFlag, "date", "d", "", "Date or date range to add entries for")
+	addCmd.RegisterFlagCompletionFunc("date", completeDate)
+	addCmd.Flags().StringVarP(&addRange, "range", "r", "", "Date range to backfill (START..END)")
+	addCmd.Flags().StringVarP(&addWeekdays, "weekdays", "w", "", "Only add on these weekdays (e.g. mon,wed,fri)")
+	addCmd.Flags().BoolVarP(&addForce, "force", "f", false, "Add a range without confirmation")
+	addCmd.Flags().StringVar(&addBy, "by", "", "Who logged the entries, for shared habits")
 }
\ No newline at end of file
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var restoreForce bool
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "List and restore automatic backups",
+	Long: `hab copies your data file into backups/ next to it before every change.
+Old copies are pruned: the most recent ones are kept, plus the newest copy of
+each recent day, week and month. Set the counts in the config file:
+
+  "backups": {"keep_last": 10, "daily": 7, "weekly": 4, "monthly": 12}
+
+Set them all to 0 to turn backups off.
+
+Examples:
+  hab backup list                      # Show available backups
+  hab backup restore 20250115-083000   # Preview and restore one`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listBackups()
+	},
+}
+
+// backupListCmd lists the available backups
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show available backups, newest first",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listBackups()
+	},
+}
+
+// backupRestoreCmd restores a backup
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Replace your habits with a backup",
+	Long: `Replace your data file with a backup, after showing which habits and entry
+counts would change. The current file is backed up first, so a restore can
+be undone by restoring that backup.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeBackupID,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		backup, err := internal.FindBackup(hm.DataFile(), args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		restored, err := hm.LoadBackup(backup)
+		if err != nil {
+			fmt.Printf("Error reading backup: %v\n", err)
+			os.Exit(1)
+		}
+
+		diffs := internal.DiffData(hm.Data(), restored)
+		if len(diffs) == 0 {
+			fmt.Println("No habits or entry counts differ from the current data.")
+		} else {
+			fmt.Printf("Restoring backup %s (%s) will change:\n", backup.ID, backup.Time.Format("Mon Jan 2 15:04"))
+			for _, diff := range diffs {
+				switch {
+				case diff.Added:
+					fmt.Printf("  + %s (%s): restore habit with %d entries\n", diff.Key, diff.Name, diff.ToEntries)
+				case diff.Removed:
+					fmt.Printf("  - %s (%s): remove habit with %d entries\n", diff.Key, diff.Name, diff.FromEntries)
+				default:
+					fmt.Printf("  ~ %s (%s): %d → %d entries (%+d)\n", diff.Key, diff.Name,
+						diff.FromEntries, diff.ToEntries, diff.ToEntries-diff.FromEntries)
+				}
+			}
+		}
+
+		if !restoreForce && !confirm("Replace your current habits with this backup?") {
+			fmt.Println("Operation cancelled")
+			return
+		}
+
+		if err := hm.RestoreBackup(backup); err != nil {
+			fmt.Printf("Error restoring backup: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Restored backup %s\n", backup.ID)
+	},
+}
+
+// listBackups prints the data file's backups
+func listBackups() {
+	dataFile := internal.NewHabitManager().DataFile()
+	backups, err := internal.ListBackups(dataFile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(backups) == 0 {
+		fmt.Println("No backups yet. One is made before each change to your habits.")
+		return
+	}
+
+	fmt.Printf("%-17s %-18s %s\n", "ID", "Created", "Size")
+	for _, backup := range backups {
+		fmt.Printf("%-17s %-18s %s\n", backup.ID, backup.Time.Format("Mon Jan 2 15:04"), formatSize(backup.Size))
+	}
+	fmt.Println("\nRestore one with: hab backup restore <id>")
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// completeBackupID suggests backup IDs, newest first
+func completeBackupID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	backups, _ := internal.ListBackups(internal.NewHabitManager().DataFile())
+	var ids []string
+	for _, backup := range backups {
+		ids = append(ids, fmt.Sprintf("%s\t%s", backup.ID, backup.Time.Format("Mon Jan 2 15:04")))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupRestoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Restore without confirmation")
+}
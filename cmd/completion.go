@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+// completionInstallCmd represents the completion install command
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish]",
+	Short: "Install the autocompletion script for your shell",
+	Long: `Write the autocompletion script to the standard location for your shell.
+The shell is detected from $SHELL unless given.
+
+Examples:
+  hab completion install         # Detect the shell
+  hab completion install zsh     # Install for zsh`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Run: func(cmd *cobra.Command, args []string) {
+		shell := filepath.Base(os.Getenv("SHELL"))
+		if len(args) > 0 {
+			shell = args[0]
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Printf("Error finding home directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		var path, hint string
+		var generate func(*os.File) error
+		switch shell {
+		case "bash":
+			dataHome := os.Getenv("XDG_DATA_HOME")
+			if dataHome == "" {
+				dataHome = filepath.Join(home, ".local", "share")
+			}
+			path = filepath.Join(dataHome, "bash-completion", "completions", "hab")
+			hint = "Restart your shell to enable completions (requires the bash-completion package)."
+			generate = func(f *os.File) error { return rootCmd.GenBashCompletionV2(f, true) }
+		case "zsh":
+			path = filepath.Join(home, ".zsh", "completions", "_hab")
+			hint = "Add this to ~/.zshrc if it isn't there already, then restart your shell:\n  fpath=(~/.zsh/completions $fpath)\n  autoload -U compinit && compinit"
+			generate = func(f *os.File) error { return rootCmd.GenZshCompletion(f) }
+		case "fish":
+			path = filepath.Join(home, ".config", "fish", "completions", "hab.fish")
+			hint = "Completions are active in new fish sessions."
+			generate = func(f *os.File) error { return rootCmd.GenFishCompletion(f, true) }
+		default:
+			fmt.Printf("Error: unsupported shell '%s'. Use bash, zsh or fish, or see 'hab completion --help'\n", shell)
+			os.Exit(1)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Printf("Error creating completion directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		file, err := os.Create(path)
+		if err != nil {
+			fmt.Printf("Error creating completion script: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		if err := generate(file); err != nil {
+			fmt.Printf("Error writing completion script: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Installed %s completions to %s\n", shell, path)
+		fmt.Println(hint)
+	},
+}
+
+// addCompletionInstall attaches the install subcommand to cobra's default
+// completion command
+func addCompletionInstall() {
+	rootCmd.InitDefaultCompletionCmd()
+	if completionCmd, _, err := rootCmd.Find([]string{"completion"}); err == nil && completionCmd != rootCmd {
+		completionCmd.AddCommand(completionInstallCmd)
+	}
+}
+
+// habitKeyCompletions lists habit keys described by their names
+func habitKeyCompletions() []string {
+	// Completion doesn't run the root command's hooks
+	useProfileFlag()
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, key := range hm.SortedKeys() {
+		activity, _ := hm.GetActivity(key)
+		completions = append(completions, key+"\t"+activity.Name)
+	}
+	return completions
+}
+
+// dateCompletions lists relative date words and the past week's dates
+func dateCompletions() []string {
+	now := time.Now()
+	completions := []string{
+		"today\t" + now.Format("2006-01-02"),
+		"yesterday\t" + now.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	for i := 0; i < 7; i++ {
+		day := now.AddDate(0, 0, -i)
+		completions = append(completions, day.Format("2006-01-02")+"\t"+day.Format("Monday"))
+	}
+	return completions
+}
+
+// completeHabitKey completes a single habit key argument
+func completeHabitKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return habitKeyCompletions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeHabitKeyThenDate completes a habit key followed by a date
+func completeHabitKeyThenDate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return habitKeyCompletions(), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return dateCompletions(), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfileName completes a single profile name argument
+func completeProfileName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	profiles, _ := internal.ListProfiles()
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDate completes a date flag value
+func completeDate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return dateCompletions(), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hab/internal"
+)
+
+// resolveEntryDates turns a date argument or --range flag, optionally limited
+// to --weekdays, into the YYYY-MM-DD dates to act on
+func resolveEntryDates(dateArg, rangeFlag, weekdaysFlag string) ([]string, error) {
+	if dateArg != "" && rangeFlag != "" {
+		return nil, fmt.Errorf("give either a date or --range, not both")
+	}
+
+	input := dateArg
+	if rangeFlag != "" {
+		input = rangeFlag
+	}
+
+	dates, err := internal.ParseDateRange(input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if weekdaysFlag != "" {
+		weekdays, err := internal.ParseWeekdayList(weekdaysFlag)
+		if err != nil {
+			return nil, err
+		}
+		dates = internal.FilterWeekdays(dates, weekdays)
+	}
+
+	return dates, nil
+}
+
+// printDatePreview lists dates compactly, several per line
+func printDatePreview(dates []string) {
+	const perLine = 5
+	for i, dateStr := range dates {
+		if i%perLine == 0 {
+			fmt.Print(" ")
+		}
+		label := dateStr
+		if date, err := time.Parse(internal.DateFormat, dateStr); err == nil {
+			label = date.Format("Mon 2006-01-02")
+		}
+		fmt.Printf(" %s", label)
+		if i%perLine == perLine-1 || i == len(dates)-1 {
+			fmt.Println()
+		}
+	}
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Printf("%s (y/N): ", prompt)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
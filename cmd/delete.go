@@ -22,7 +22,8 @@ By default, you'll be prompted for confirmation unless --force is used.
 Examples:
   hab delete exercise      # Delete with confirmation
   hab delete exercise -f   # Delete without confirmation`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeHabitKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		habitKey := args[0]
 
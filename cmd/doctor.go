@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var doctorFix bool
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your habit data for problems and repair them",
+	Long: `Check every habit for problems that hab would otherwise accept silently:
+
+  - malformed dates (normalised when recognisable, e.g. 2025/1/5, else removed)
+  - future dates (removed)
+  - more entries on a day than the target (trimmed to the target, like prune)
+  - unknown colors (lowercased if that helps, else set to green)
+  - targets below 1 (set to 1)
+  - empty names (derived from the key)
+  - invalid reminder times (removed)
+
+Without --fix, problems are only reported and the command exits with status 1
+if there are any. A backup is made before repairs are saved.
+
+Examples:
+  hab doctor          # Report problems
+  hab doctor --fix    # Repair them`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		today := time.Now()
+		issues := hm.Diagnose(today)
+		if len(issues) == 0 {
+			fmt.Println("✓ No problems found")
+			return
+		}
+
+		if doctorFix {
+			var err error
+			if issues, err = hm.Repair(today); err != nil {
+				fmt.Printf("Error repairing habits: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		habits := 0
+		lastKey := ""
+		for _, issue := range issues {
+			if issue.Key != lastKey {
+				activity, _ := hm.GetActivity(issue.Key)
+				fmt.Printf("%s (%s)\n", issue.Key, activity.Name)
+				lastKey = issue.Key
+				habits++
+			}
+			fmt.Printf("  %s\n", issue)
+		}
+
+		if doctorFix {
+			fmt.Printf("\n✓ Repaired %d problems in %d habits\n", len(issues), habits)
+			return
+		}
+		fmt.Printf("\n✗ Found %d problems in %d habits. Run 'hab doctor --fix' to repair them.\n", len(issues), habits)
+		os.Exit(1)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair the problems found")
+}
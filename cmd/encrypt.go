@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var encryptNewKey string
+
+// encryptCmd represents the encrypt command
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt your habit data file",
+	Long: `Encrypt activities.json with a passphrase or an age key file. Once it is
+encrypted, every command decrypts and re-encrypts it transparently using
+the first of these that is set:
+
+  HAB_KEY_FILE             path to an age key file
+  HAB_PASSPHRASE           the passphrase itself
+  HAB_PASSPHRASE_COMMAND   a command that prints the passphrase, such as
+                           "secret-tool lookup service hab" or "pass show hab"
+                           (or "passphrase_command" in the config file)
+
+Only JSON storage can be encrypted. Use 'hab decrypt' to undo it.
+
+Examples:
+  HAB_PASSPHRASE='correct horse' hab encrypt
+  hab encrypt --new-key ~/.config/hab/key.txt   # Then export HAB_KEY_FILE`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dataFile := jsonDataFile()
+
+		var keys *internal.Keys
+		if encryptNewKey != "" {
+			if err := internal.GenerateKeyFile(encryptNewKey); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ Created key file %s\n", encryptNewKey)
+			keys = &internal.Keys{KeyFile: encryptNewKey}
+		} else {
+			keys = loadKeys()
+		}
+
+		if err := internal.EncryptFile(dataFile, keys); err != nil {
+			fmt.Printf("Error encrypting %s: %v\n", dataFile, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Encrypted %s\n", dataFile)
+		warnPlaintextBackups(dataFile)
+		if encryptNewKey != "" {
+			fmt.Printf("Keep the key file safe and set HAB_KEY_FILE=%s to use your habits\n", encryptNewKey)
+		}
+	},
+}
+
+// decryptCmd represents the decrypt command
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store your habit data file unencrypted again",
+	Long: `Decrypt activities.json in place using the configured passphrase or key
+file (see 'hab encrypt --help').
+
+Examples:
+  HAB_PASSPHRASE='correct horse' hab decrypt`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dataFile := jsonDataFile()
+
+		if err := internal.DecryptFile(dataFile, loadKeys()); err != nil {
+			fmt.Printf("Error decrypting %s: %v\n", dataFile, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Decrypted %s\n", dataFile)
+	},
+}
+
+// warnPlaintextBackups points out backups made before the file was
+// encrypted, which still hold habits in the clear
+func warnPlaintextBackups(dataFile string) {
+	backups, _ := internal.ListBackups(dataFile)
+	plaintext := 0
+	for _, backup := range backups {
+		if !internal.IsEncryptedFile(backup.Path) {
+			plaintext++
+		}
+	}
+	if plaintext > 0 {
+		fmt.Printf("Note: %d earlier backups in %s are not encrypted; delete them if they shouldn't be kept\n",
+			plaintext, filepath.Dir(backups[0].Path))
+	}
+}
+
+// jsonDataFile returns the JSON data file, exiting if another storage
+// backend is in use
+func jsonDataFile() string {
+	store, err := internal.OpenStore()
+	if err != nil {
+		fmt.Printf("Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+	if _, ok := store.(*internal.JSONStore); !ok {
+		fmt.Println("Error: only JSON storage can be encrypted, run 'hab migrate --to json' first")
+		os.Exit(1)
+	}
+	if _, err := os.Stat(store.Path()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return store.Path()
+}
+
+// loadKeys returns the configured passphrase or key file, exiting if none
+// is set
+func loadKeys() *internal.Keys {
+	keys, err := internal.LoadKeys()
+	if errors.Is(err, internal.ErrNoKey) {
+		fmt.Println("Error: set HAB_PASSPHRASE, HAB_KEY_FILE or HAB_PASSPHRASE_COMMAND, or use --new-key")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	encryptCmd.Flags().StringVar(&encryptNewKey, "new-key", "", "Create a new age key file at this path and encrypt with it")
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	goalIn        string
+	goalFrom      string
+	goalTo        string
+	goalChallenge bool
+	goalID        string
+)
+
+// goalCmd represents the goal command
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Set goals and challenges for habits and track their progress",
+	Long: `Goals give a habit a target over a period: a number of entries by a deadline,
+or a challenge to meet the daily target every day for a number of days.
+Progress, the pace needed to finish, a projected completion date and whether
+the goal passed or failed are worked out from the habit's entries.
+
+Examples:
+  hab goal add exercise 100 --in 2025                    # 100 entries in 2025
+  hab goal add reading 20 --to 2025-06-30                # 20 entries by June 30
+  hab goal add meditation 30 --challenge --from 2025-03-01
+  hab goal list                                          # All goals
+  hab goal show exercise-1                               # Progress and pace
+  hab goal delete exercise-1`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listGoals("")
+	},
+}
+
+// goalAddCmd adds a goal
+var goalAddCmd = &cobra.Command{
+	Use:   "add <habit> <target>",
+	Short: "Add a goal for a habit",
+	Long: `Add a goal for a habit. The target is a number of entries between --from
+(default today) and --to, or within a year or month given with --in. With
+--challenge the target is a number of days starting --from, each of which
+must meet the habit's daily target. Habits to avoid only take challenges,
+which count days without a slip from --from, even before the habit was
+created.
+
+The goal is named <habit>-1, <habit>-2... unless --id is given.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		activity, exists := hm.GetActivity(args[0])
+		if !exists {
+			fmt.Printf("Error: habit '%s' does not exist\n", args[0])
+			os.Exit(1)
+		}
+
+		target, err := strconv.Atoi(args[1])
+		if err != nil || target < 1 {
+			fmt.Printf("Error: target must be a positive number, got '%s'\n", args[1])
+			os.Exit(1)
+		}
+
+		goal, err := buildGoal(args[0], target, time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		id, err := hm.AddGoal(goalID, goal)
+		if err != nil {
+			fmt.Printf("Error adding goal: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Added goal '%s' for '%s': %s\n", id, activity.Name, goal.Describe())
+	},
+}
+
+// goalListCmd lists goals
+var goalListCmd = &cobra.Command{
+	Use:               "list [habit]",
+	Short:             "Show goals with their progress",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		habitKey := ""
+		if len(args) > 0 {
+			habitKey = args[0]
+		}
+		listGoals(habitKey)
+	},
+}
+
+// goalShowCmd shows one goal in detail
+var goalShowCmd = &cobra.Command{
+	Use:               "show <id>",
+	Short:             "Show a goal's progress, pace and projection",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGoalID,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		progress, err := hm.GoalProgress(args[0], time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		goal := progress.Goal
+		activity, _ := hm.GetActivity(goal.Habit)
+
+		title := fmt.Sprintf("Goal '%s' for '%s'", args[0], activity.Name)
+		fmt.Println(title)
+		fmt.Println(strings.Repeat("=", len(title)))
+		fmt.Printf("Target: %s (%d days)\n", goal.Describe(), progress.Days)
+		fmt.Printf("Progress: %d/%d %s (%.0f%%)\n", progress.Done, goal.Target, goalUnit(goal), progress.Percent()*100)
+		fmt.Printf("Status: %s\n", progress.StatusText())
+
+		switch progress.Status {
+		case internal.GoalPassed:
+			fmt.Printf("Reached on %s\n", progress.Projected.Format(internal.DateFormat))
+		case internal.GoalActive:
+			fmt.Printf("Days left: %d\n", progress.DaysLeft)
+			if goal.Kind == internal.GoalTotal {
+				fmt.Printf("Pace so far: %.2f entries/day\n", progress.CurrentPace)
+				fmt.Printf("Pace needed: %.2f entries/day\n", progress.RequiredPace)
+			}
+			if progress.Projected.IsZero() {
+				fmt.Println("Projected: no entries yet to project from")
+			} else {
+				fmt.Printf("Projected: done on %s\n", progress.Projected.Format(internal.DateFormat))
+			}
+		case internal.GoalUpcoming:
+			fmt.Printf("Starts on %s\n", goal.Start)
+		}
+	},
+}
+
+// goalDeleteCmd deletes a goal
+var goalDeleteCmd = &cobra.Command{
+	Use:               "delete <id>",
+	Short:             "Delete a goal (the habit's entries are kept)",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGoalID,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := hm.DeleteGoal(args[0]); err != nil {
+			fmt.Printf("Error deleting goal: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Deleted goal '%s'\n", args[0])
+	},
+}
+
+// buildGoal makes a goal from the add command's flags
+func buildGoal(habitKey string, target int, now time.Time) (internal.Goal, error) {
+	goal := internal.Goal{Habit: habitKey, Kind: internal.GoalTotal, Target: target}
+
+	start, err := internal.ParseDate(goalFrom, now)
+	if err != nil {
+		return goal, err
+	}
+
+	switch {
+	case goalChallenge:
+		if goalIn != "" || goalTo != "" {
+			return goal, fmt.Errorf("a challenge lasts <target> days, so --in and --to can't be used with --challenge")
+		}
+		goal.Kind = internal.GoalDaily
+		goal.Start = start.Format(internal.DateFormat)
+		goal.End = start.AddDate(0, 0, target-1).Format(internal.DateFormat)
+
+	case goalIn != "":
+		if goalFrom != "" || goalTo != "" {
+			return goal, fmt.Errorf("use either --in or --from/--to")
+		}
+		periodStart, periodEnd, err := internal.ParsePeriod(goalIn, now.Location())
+		if err != nil {
+			return goal, err
+		}
+		goal.Start = periodStart.Format(internal.DateFormat)
+		goal.End = periodEnd.Format(internal.DateFormat)
+
+	case goalTo != "":
+		end, err := internal.ParseDate(goalTo, now)
+		if err != nil {
+			return goal, err
+		}
+		goal.Start = start.Format(internal.DateFormat)
+		goal.End = end.Format(internal.DateFormat)
+
+	default:
+		return goal, fmt.Errorf("give a deadline with --to or --in, or use --challenge")
+	}
+	return goal, nil
+}
+
+// listGoals prints goals with their progress, only a habit's if key is set
+func listGoals(habitKey string) {
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		fmt.Printf("Error loading habits: %v\n", err)
+		os.Exit(1)
+	}
+
+	ids := hm.GoalIDs()
+	if habitKey != "" {
+		ids = hm.HabitGoalIDs(habitKey)
+	}
+	if len(ids) == 0 {
+		fmt.Println("No goals yet. Add one with: hab goal add [habit] [target] --in 2025")
+		return
+	}
+
+	// Size the columns to fit the longest ID and description
+	now := time.Now()
+	idWidth, goalWidth := len("ID"), len("Goal")
+	for _, id := range ids {
+		goal, _ := hm.GetGoal(id)
+		idWidth = max(idWidth, len(id))
+		goalWidth = max(goalWidth, len(goal.Habit+": "+goal.Describe()))
+	}
+
+	fmt.Printf("%-*s  %-*s  %-14s %s\n", idWidth, "ID", goalWidth, "Goal", "Progress", "Status")
+	for _, id := range ids {
+		progress, _ := hm.GoalProgress(id, now)
+		done := fmt.Sprintf("%d/%d %3.0f%%", progress.Done, progress.Goal.Target, progress.Percent()*100)
+		fmt.Printf("%-*s  %-*s  %-14s %s\n", idWidth, id, goalWidth, progress.Goal.Habit+": "+progress.Goal.Describe(),
+			done, progress.StatusText())
+	}
+	fmt.Println("\nSee a goal's pace and projection with: hab goal show <id>")
+}
+
+// goalUnit names what a goal counts
+func goalUnit(goal internal.Goal) string {
+	if goal.Kind == internal.GoalDaily {
+		return "days"
+	}
+	return "entries"
+}
+
+// completeGoalID completes a single goal ID argument
+func completeGoalID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	useProfileFlag()
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, id := range hm.GoalIDs() {
+		goal, _ := hm.GetGoal(id)
+		completions = append(completions, id+"\t"+goal.Describe())
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(goalCmd)
+	goalCmd.AddCommand(goalAddCmd)
+	goalCmd.AddCommand(goalListCmd)
+	goalCmd.AddCommand(goalShowCmd)
+	goalCmd.AddCommand(goalDeleteCmd)
+
+	goalAddCmd.Flags().StringVar(&goalIn, "in", "", "Year or month the goal covers (2025, 2025-03)")
+	goalAddCmd.Flags().StringVar(&goalFrom, "from", "", "First day of the goal (default today)")
+	goalAddCmd.Flags().StringVar(&goalTo, "to", "", "Deadline, the goal's last day")
+	goalAddCmd.Flags().BoolVar(&goalChallenge, "challenge", false, "Meet the daily target every day for <target> days")
+	goalAddCmd.Flags().StringVar(&goalID, "id", "", "Name for the goal (default <habit>-N)")
+	goalAddCmd.RegisterFlagCompletionFunc("from", completeDate)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+	"hab/ui"
+)
+
+var (
+	imageFormat   string
+	imageTimeline string
+	imageOut      string
+	badgeLabel    string
+	badgeOut      string
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image [habit]",
+	Short: "Export a habit's contribution grid as an SVG or PNG image",
+	Long: `Export a habit's contribution grid as an image, using the habit's color and
+the same completion levels as the interactive grid.
+
+Examples:
+  hab image exercise                         # Write exercise.svg
+  hab image exercise --format png -t 6m      # Write exercise.png for 6 months
+  hab image exercise --out - > grid.svg      # Write to stdout`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		habitKey := args[0]
+
+		timeline, err := parseTimeline(imageTimeline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		var write func(io.Writer, internal.Activity, ui.TimelineDays) error
+		switch imageFormat {
+		case "svg":
+			write = ui.WriteHeatmapSVG
+		case "png":
+			write = ui.WriteHeatmapPNG
+		default:
+			fmt.Fprintf(os.Stderr, "Invalid format '%s'. Use svg or png\n", imageFormat)
+			os.Exit(1)
+		}
+
+		activity := loadActivity(habitKey)
+
+		out := imageOut
+		if out == "" {
+			out = habitKey + "." + imageFormat
+		}
+		writeOutput(out, func(w io.Writer) error {
+			return write(w, activity, timeline)
+		})
+	},
+}
+
+// badgeCmd represents the badge command
+var badgeCmd = &cobra.Command{
+	Use:   "badge [habit]",
+	Short: "Export a shields-style SVG badge with a habit's current streak",
+	Long: `Export a small SVG badge showing a habit's current streak, for READMEs
+and personal sites.
+
+Examples:
+  hab badge exercise                     # Write exercise-streak.svg ("streak | 42d")
+  hab badge exercise --label workouts    # Custom label
+  hab badge exercise --out -             # Write to stdout`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		habitKey := args[0]
+
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		activity, exists := hm.GetActivity(habitKey)
+		if !exists {
+			fmt.Printf("Error: habit '%s' does not exist\n", habitKey)
+			os.Exit(1)
+		}
+
+		stats, err := hm.GetStats(habitKey)
+		if err != nil {
+			fmt.Printf("Error getting statistics: %v\n", err)
+			os.Exit(1)
+		}
+
+		badge := ui.RenderBadgeSVG(badgeLabel, fmt.Sprintf("%dd", stats["current_streak"]), activity.Color)
+
+		out := badgeOut
+		if out == "" {
+			out = habitKey + "-streak.svg"
+		}
+		writeOutput(out, func(w io.Writer) error {
+			_, err := io.WriteString(w, badge)
+			return err
+		})
+	},
+}
+
+// loadActivity loads the habit with the given key or exits with an error
+func loadActivity(habitKey string) internal.Activity {
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		fmt.Printf("Error loading habits: %v\n", err)
+		os.Exit(1)
+	}
+
+	activity, exists := hm.GetActivity(habitKey)
+	if !exists {
+		fmt.Printf("Error: habit '%s' does not exist\n", habitKey)
+		os.Exit(1)
+	}
+	return activity
+}
+
+// writeOutput writes to the named file, or stdout when the name is "-"
+func writeOutput(out string, write func(io.Writer) error) {
+	if out == "-" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		fmt.Printf("Error creating %s: %v\n", out, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if err := write(file); err != nil {
+		fmt.Printf("Error writing %s: %v\n", out, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Wrote %s\n", out)
+}
+
+func init() {
+	rootCmd.AddCommand(imageCmd)
+	rootCmd.AddCommand(badgeCmd)
+
+	imageCmd.Flags().StringVarP(&imageFormat, "format", "f", "svg", "Image format (svg, png)")
+	imageCmd.Flags().StringVarP(&imageTimeline, "timeline", "t", "12m", "Timeline to display (3m, 6m, 12m)")
+	imageCmd.Flags().StringVarP(&imageOut, "out", "o", "", "File to write, or - for stdout (default [habit].[format])")
+
+	badgeCmd.Flags().StringVar(&badgeLabel, "label", "streak", "Text on the left side of the badge")
+	badgeCmd.Flags().StringVarP(&badgeOut, "out", "o", "", "File to write, or - for stdout (default [habit]-streak.svg)")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var mergeOutput string
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <base> <ours> <theirs>",
+	Short: "Merge two conflicting copies of your habit data",
+	Long: `Three-way merge two edited copies of activities.json using the copy they
+both started from, such as a Syncthing or Dropbox conflict file and the
+last backup. The result goes to stdout unless --out is given, and is
+encrypted with the configured key if any of the inputs was encrypted.
+
+Rules:
+  - Habits added or deleted on one side are added or deleted
+  - Entries are merged per day: when both sides logged more on a day the
+    larger count wins, since they're usually the same check-ins; when both
+    removed some the smaller wins; otherwise both changes apply. Who logged
+    each day's entries is merged the same way
+  - Metadata changed on one side wins; when both changed it, ours keeps its
+    name, color and polarity, the higher target wins and reminder times are
+    combined
+  - A habit deleted on one side but changed on the other is kept and
+    reported as an unresolved conflict
+
+Exits with status 1 when unresolved conflicts remain, so it works as a git
+merge driver:
+
+  git config merge.hab.driver 'hab merge %O %A %B -o %A'
+  echo 'activities.json merge=hab' >> .gitattributes
+
+Examples:
+  hab merge base.json activities.json "activities (conflict).json" -o activities.json
+  hab merge old.json mine.json theirs.json > merged.json`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		var sides [3]*internal.ActivitiesData
+		encrypted := false
+		for i, path := range args {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+				os.Exit(2)
+			}
+			encrypted = encrypted || internal.IsEncrypted(contents)
+			if sides[i], err = internal.ParseActivitiesData(contents); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+				os.Exit(2)
+			}
+		}
+
+		merged, conflicts := internal.Merge(sides[0], sides[1], sides[2])
+
+		contents, err := internal.EncodeActivitiesData(merged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		contents = append(contents, '\n')
+
+		// Keep the result encrypted if any input was, so a merge never
+		// writes the habits out in plain text
+		if encrypted {
+			keys, err := internal.LoadKeys()
+			if err == nil {
+				contents, err = internal.Encrypt(contents, keys)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+		}
+		writeOutput(mergeOutput, func(w io.Writer) error {
+			_, err := w.Write(contents)
+			return err
+		})
+
+		// Keep stdout for the merged data
+		unresolved := 0
+		for _, conflict := range conflicts {
+			if conflict.Resolved {
+				fmt.Fprintf(os.Stderr, "resolved: %s\n", conflict)
+			} else {
+				fmt.Fprintf(os.Stderr, "CONFLICT: %s\n", conflict)
+				unresolved++
+			}
+		}
+		if unresolved > 0 {
+			fmt.Fprintf(os.Stderr, "%d unresolved conflicts, check the merged habits\n", unresolved)
+			os.Exit(1)
+		}
+	},
+}
+
+// printMergeConflicts lists the differences a merge settled or couldn't
+func printMergeConflicts(conflicts []internal.MergeConflict) {
+	for _, conflict := range conflicts {
+		if conflict.Resolved {
+			fmt.Printf("  Resolved %s\n", conflict)
+		} else {
+			fmt.Printf("  ⚠ Conflict %s\n", conflict)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().StringVarP(&mergeOutput, "out", "o", "-", "Output file, or - for stdout")
+}
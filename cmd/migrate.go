@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	migrateTo    string
+	migrateForce bool
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --to sqlite|json",
+	Short: "Convert your habit data to another storage backend",
+	Long: `Copy all habits and entries from the current storage backend to another
+one, check the copy matches, and switch to it. The old file is left in place.
+
+The SQLite database is stored next to the JSON file with a .db extension.
+Setting HAB_STORAGE=json or HAB_STORAGE=sqlite overrides the saved choice.
+
+Examples:
+  hab migrate --to sqlite      # Move from activities.json to activities.db
+  hab migrate --to json        # Move back to the JSON file
+  hab migrate --to json -f     # Overwrite an existing JSON file`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := internal.ConfiguredStorage()
+		if err != nil {
+			fmt.Printf("Error reading config: %v\n", err)
+			os.Exit(1)
+		}
+		if from == migrateTo {
+			fmt.Printf("Already using %s storage\n", migrateTo)
+			return
+		}
+
+		target, err := internal.NewStore(migrateTo)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		if migrateTo == internal.StorageSQLite && internal.IsEncryptedFile(hm.DataFile()) {
+			fmt.Println("Error: SQLite storage can't be encrypted, run 'hab decrypt' first to store habits unencrypted")
+			os.Exit(1)
+		}
+
+		// Don't silently replace habits already in the target
+		if _, err := os.Stat(target.Path()); err == nil && !migrateForce {
+			keys, err := target.List()
+			if err != nil {
+				fmt.Printf("Error reading %s: %v\n", target.Path(), err)
+				os.Exit(1)
+			}
+			if len(keys) > 0 {
+				fmt.Printf("Error: %s already holds %d habits, use --force to overwrite it\n", target.Path(), len(keys))
+				os.Exit(1)
+			}
+		}
+
+		if err := target.Save(hm.Data()); err != nil {
+			fmt.Printf("Error writing %s: %v\n", target.Path(), err)
+			os.Exit(1)
+		}
+
+		copied, err := target.Load()
+		if err != nil {
+			fmt.Printf("Error verifying %s: %v\n", target.Path(), err)
+			os.Exit(1)
+		}
+		if !copied.Equal(hm.Data()) {
+			fmt.Printf("Error: the copy in %s doesn't match the original, storage not switched\n", target.Path())
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error reading config: %v\n", err)
+			os.Exit(1)
+		}
+		config.Storage = migrateTo
+		if err := config.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries := 0
+		for _, activity := range hm.GetActivities() {
+			entries += len(activity.Dates)
+		}
+
+		fmt.Printf("✓ Migrated %d habits (%d entries) from %s to %s\n", len(hm.GetActivities()), entries, from, migrateTo)
+		fmt.Printf("Data is now stored in %s\n", target.Path())
+		fmt.Printf("The old data in %s was left in place\n", hm.DataFile())
+		if storage := os.Getenv("HAB_STORAGE"); storage != "" && storage != migrateTo {
+			fmt.Printf("Note: HAB_STORAGE=%s still overrides this setting\n", storage)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Storage backend to migrate to (sqlite or json)")
+	migrateCmd.Flags().BoolVarP(&migrateForce, "force", "f", false, "Overwrite habits already in the target")
+	migrateCmd.MarkFlagRequired("to")
+	migrateCmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions(
+		[]string{internal.StorageSQLite, internal.StorageJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -14,6 +14,7 @@ import (
 var (
 	color        string
 	targetPerDay int
+	avoidHabit   bool
 )
 
 // newCmd represents the new command
@@ -26,7 +27,8 @@ or you'll be prompted to enter it interactively.
 Examples:
   hab new exercise              # Create a habit called 'exercise'
   hab new --color red exercise  # Create with red color
-  hab new --target 2 brushing   # Create with target of 2 times per day`,
+  hab new --target 2 brushing   # Create with target of 2 times per day
+  hab new --avoid smoking       # Create a habit to avoid; entries are slips`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		hm := internal.NewHabitManager()
@@ -64,30 +66,39 @@ Examples:
 			color = promptForColor()
 		}
 
-		if targetPerDay == 0 {
+		// Habits to avoid have no daily target to prompt for
+		if targetPerDay == 0 && !avoidHabit {
 			targetPerDay = promptForTarget()
 		}
 
 		// Validate color
-		validColors := []string{"red", "blue", "green", "magenta", "cyan", "yellow"}
-		colorValid := false
-		for _, validColor := range validColors {
-			if color == validColor {
-				colorValid = true
-				break
-			}
-		}
-		if !colorValid {
-			fmt.Printf("Error: invalid color '%s'. Valid colors: %s\n", color, strings.Join(validColors, ", "))
+		if !internal.IsValidColor(color) {
+			fmt.Printf("Error: invalid color '%s'. Valid colors: %s\n", color, strings.Join(internal.ValidColors, ", "))
 			os.Exit(1)
 		}
 
 		// Create the habit
-		if err := hm.CreateActivity(habitKey, habitName, color, targetPerDay); err != nil {
+		polarity := internal.PolarityBuild
+		if avoidHabit {
+			polarity = internal.PolarityAvoid
+		}
+		err := hm.Mutate(func(tx *internal.Tx) error {
+			if err := tx.CreateActivity(habitKey, habitName, color, targetPerDay); err != nil {
+				return err
+			}
+			return tx.SetPolarity(habitKey, polarity)
+		})
+		if err != nil {
 			fmt.Printf("Error creating habit: %v\n", err)
 			os.Exit(1)
 		}
 
+		if avoidHabit {
+			fmt.Printf("✓ Created habit to avoid '%s' with color %s\n", habitName, color)
+			fmt.Printf("Days without entries count as success. Log a slip with: hab %s\n", habitKey)
+			return
+		}
+
 		fmt.Printf("✓ Created habit '%s' with color %s", habitName, color)
 		if targetPerDay > 1 {
 			fmt.Printf(" (target: %d times per day)", targetPerDay)
@@ -134,4 +145,5 @@ func init() {
 
 	newCmd.Flags().StringVarP(&color, "color", "c", "", "Color for the habit (red, blue, green, magenta, cyan, yellow)")
 	newCmd.Flags().IntVarP(&targetPerDay, "target", "t", 0, "Target number of times per day")
+	newCmd.Flags().BoolVar(&avoidHabit, "avoid", false, "Track a habit to avoid: entries are slips, days without them count as success")
 }
\ No newline at end of file
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var forceDeleteProfile bool
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles, each with its own habits and config",
+	Long: `Profiles keep separate sets of habits, e.g. for work and home. Each has its
+own data file, config, backups and sync repository. The profile used is, in
+order: --profile, $HAB_PROFILE, then the one chosen with 'hab profile use'.
+HAB_DATA_FILE and HAB_CONFIG_FILE still override the profile's paths.
+
+Examples:
+  hab profile create work      # Create a profile
+  hab --profile work new code  # Add a habit to it
+  hab profile use work         # Use it by default
+  hab profile list             # Show profiles
+  hab profile delete work      # Delete it and its habits`,
+	Args: cobra.NoArgs,
+	// Profile commands must work even if the active profile is missing
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		useProfileFlag()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		listProfiles()
+	},
+}
+
+// profileListCmd lists the profiles
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show profiles, marking the active one",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listProfiles()
+	},
+}
+
+// profileCreateCmd creates a profile
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := internal.CreateProfile(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Created profile '%s' in %s\n", profile.Name, profile.Dir)
+		fmt.Printf("Use it with 'hab --profile %s' or 'hab profile use %s'\n", profile.Name, profile.Name)
+	},
+}
+
+// profileUseCmd sets the default profile
+var profileUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Use a profile when --profile isn't given",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileName,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.SetDefaultProfile(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Now using profile '%s'\n", args[0])
+		if env := os.Getenv("HAB_PROFILE"); env != "" && env != args[0] {
+			fmt.Printf("Note: HAB_PROFILE is set to '%s', which takes precedence\n", env)
+		}
+	},
+}
+
+// profileDeleteCmd deletes a profile
+var profileDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a profile and all its habits",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileName,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if !internal.ProfileExists(name) {
+			fmt.Printf("Error: profile '%s' does not exist\n", name)
+			os.Exit(1)
+		}
+
+		if !forceDeleteProfile && !confirm(fmt.Sprintf("Are you sure you want to delete profile '%s' and all its habits?", name)) {
+			fmt.Println("Operation cancelled")
+			return
+		}
+
+		if err := internal.DeleteProfile(name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Deleted profile '%s'\n", name)
+	},
+}
+
+// listProfiles prints the profiles, marking the active one
+func listProfiles() {
+	profiles, err := internal.ListProfiles()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	active := internal.ActiveProfile()
+	for _, profile := range profiles {
+		marker := " "
+		if profile.Name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-15s %s\n", marker, profile.Name, profile.Dir)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	profileDeleteCmd.Flags().BoolVarP(&forceDeleteProfile, "force", "f", false, "Delete without confirmation")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var promptFormat string
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact summary of today's habits for shell prompts",
+	Long: `Print a short segment such as "hab:2/5 🔥12" showing how many habits are done
+today, how many exist, and the longest active streak. The result is cached
+until your habits change, so it is cheap to run on every prompt. Nothing is
+printed when you have no habits.
+
+Format placeholders: {done}, {total}, {remaining}, {streak} and {fire}, which
+expands to " 🔥<streak>" only while a streak is active.
+
+Examples:
+  # bash (~/.bashrc)
+  PS1='$(hab prompt) '"$PS1"
+
+  # zsh (~/.zshrc)
+  setopt PROMPT_SUBST
+  PROMPT='$(hab prompt) '"$PROMPT"
+
+  # fish (~/.config/fish/functions/fish_right_prompt.fish)
+  function fish_right_prompt; hab prompt; end
+
+  # starship (~/.config/starship.toml)
+  [custom.hab]
+  command = "hab prompt"
+  when = true
+
+  hab prompt --format '{remaining} left'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Stay silent on errors rather than breaking the user's prompt
+		summary, err := internal.LoadPromptSummary()
+		if err != nil || summary.Total == 0 {
+			return
+		}
+
+		fire := ""
+		if summary.LongestStreak > 0 {
+			fire = " 🔥" + strconv.Itoa(summary.LongestStreak)
+		}
+
+		output := strings.NewReplacer(
+			"{done}", strconv.Itoa(summary.Done),
+			"{total}", strconv.Itoa(summary.Total),
+			"{remaining}", strconv.Itoa(summary.Remaining()),
+			"{streak}", strconv.Itoa(summary.LongestStreak),
+			"{fire}", fire,
+		).Replace(promptFormat)
+		fmt.Println(output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+
+	promptCmd.Flags().StringVarP(&promptFormat, "format", "f", "hab:{done}/{total}{fire}", "Output format")
+}
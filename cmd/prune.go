@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"hab/internal"
@@ -21,7 +22,7 @@ var pruneCmd = &cobra.Command{
 	
 For each day where a habit has more entries than its target_per_day setting,
 this command will remove the excess entries, keeping only the number specified
-by target_per_day.
+by target_per_day. 'hab doctor' checks for this and other problems.
 
 Examples:
   hab prune                    # Prune all habits (with confirmation)
@@ -58,15 +59,27 @@ Examples:
 			}
 		}
 
+		sort.Strings(habitsToProcess)
+
+		// Confirm each habit's removals, then make them all in one batch so
+		// the data file is written and backed up once
+		toPrune := make(map[string]map[string]int)
 		totalPruned := 0
 		for _, habitKey := range habitsToProcess {
-			activity := activities[habitKey]
-			pruned, err := pruneHabit(hm, habitKey, activity, pruneDryRun, pruneForce)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error pruning habit '%s': %v\n", habitKey, err)
-				continue
+			excess := pruneHabit(activities[habitKey], pruneDryRun, pruneForce)
+			for _, count := range excess {
+				totalPruned += count
+			}
+			if len(excess) > 0 {
+				toPrune[habitKey] = excess
+			}
+		}
+
+		if !pruneDryRun && len(toPrune) > 0 {
+			if err := removeExcess(hm, toPrune); err != nil {
+				fmt.Fprintf(os.Stderr, "Error pruning habits: %v\n", err)
+				os.Exit(1)
 			}
-			totalPruned += pruned
 		}
 
 		if pruneDryRun {
@@ -78,16 +91,13 @@ Examples:
 			fmt.Println("\nNo excess entries found to prune.")
 		}
 	},
-	Args: cobra.MaximumNArgs(1),
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeHabitKey,
 }
 
-func pruneHabit(hm *internal.HabitManager, habitKey string, activity internal.Activity, dryRun, force bool) (int, error) {
-	// Count entries per date
-	dateCounts := make(map[string]int)
-	for _, date := range activity.Dates {
-		dateCounts[date]++
-	}
-
+// pruneHabit shows a habit's excess entries and returns how many to remove
+// on each date, nothing if the user declines
+func pruneHabit(activity internal.Activity, dryRun, force bool) map[string]int {
 	// Determine target (default to 1 if not set)
 	target := activity.TargetPerDay
 	if target == 0 {
@@ -95,29 +105,28 @@ func pruneHabit(hm *internal.HabitManager, habitKey string, activity internal.Ac
 	}
 
 	// Find dates with excess entries
+	excess := internal.ExcessEntries(activity)
 	var excessDates []string
 	totalExcess := 0
-	for date, count := range dateCounts {
-		if count > target {
-			excessDates = append(excessDates, date)
-			totalExcess += count - target
-		}
+	for date, count := range excess {
+		excessDates = append(excessDates, date)
+		totalExcess += count
 	}
+	sort.Strings(excessDates)
 
 	if len(excessDates) == 0 {
-		return 0, nil
+		return nil
 	}
 
 	// Show what will be pruned
 	fmt.Printf("\nHabit: %s (target: %d per day)\n", activity.Name, target)
 	for _, date := range excessDates {
-		excess := dateCounts[date] - target
 		fmt.Printf("  %s: %d entries → %d entries (removing %d)\n", 
-			date, dateCounts[date], target, excess)
+			date, excess[date]+target, target, excess[date])
 	}
 
 	if dryRun {
-		return totalExcess, nil
+		return excess
 	}
 
 	// Confirm unless force flag is used
@@ -127,23 +136,28 @@ func pruneHabit(hm *internal.HabitManager, habitKey string, activity internal.Ac
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" && response != "yes" {
 			fmt.Println("Cancelled.")
-			return 0, nil
+			return nil
 		}
 	}
 
-	// Remove excess entries
-	actualPruned := 0
-	for _, date := range excessDates {
-		excess := dateCounts[date] - target
-		for i := 0; i < excess; i++ {
-			if err := hm.RemoveEntry(habitKey, date); err != nil {
-				return actualPruned, fmt.Errorf("failed to remove entry for %s: %w", date, err)
+	return excess
+}
+
+// removeExcess removes the given number of entries on each date of each
+// habit with a single write
+func removeExcess(hm *internal.HabitManager, toPrune map[string]map[string]int) error {
+	return hm.Mutate(func(tx *internal.Tx) error {
+		for habitKey, excess := range toPrune {
+			for date, count := range excess {
+				for i := 0; i < count; i++ {
+					if err := tx.RemoveEntry(habitKey, date); err != nil {
+						return fmt.Errorf("failed to remove entry for '%s' on %s: %w", habitKey, date, err)
+					}
+				}
 			}
-			actualPruned++
 		}
-	}
-
-	return actualPruned, nil
+		return nil
+	})
 }
 
 func init() {
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	remindDaemon   bool
+	remindCommand  string
+	remindInterval time.Duration
+)
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Show reminder times or run the reminder daemon",
+	Long: `Show each habit's reminder times, or run a daemon that sends a notification
+at every reminder time for habits whose daily target isn't met yet.
+
+On Linux notifications go through the desktop notification service. Use
+--command to run your own notifier instead when that isn't available; it
+receives the text in HAB_NOTIFY_TITLE and HAB_NOTIFY_BODY. The daemon picks
+up changes to your habits without restarting.
+
+Habits with a target above 1 can have several reminder times; the target is
+spread across them, so each reminder only fires if you're behind.
+
+Examples:
+  hab remind                                   # List reminder times
+  hab remind set exercise 07:30                # Remind at 07:30
+  hab remind set brushing 08:00 21:00          # Two reminders for a twice-daily habit
+  hab remind clear exercise                    # Remove reminders
+  hab remind --daemon                          # Run in the foreground
+  hab remind --daemon --command 'say "$HAB_NOTIFY_TITLE"'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if remindDaemon {
+			runReminderDaemon()
+			return
+		}
+
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		found := false
+		for _, key := range hm.SortedKeys() {
+			activity, _ := hm.GetActivity(key)
+			if len(activity.Reminders) == 0 {
+				continue
+			}
+			found = true
+			fmt.Printf("%s (%s): %s\n", activity.Name, key, strings.Join(activity.Reminders, ", "))
+		}
+
+		if !found {
+			fmt.Println("No reminders set. Add one with: hab remind set [habit] [HH:MM]")
+		}
+	},
+}
+
+// remindSetCmd represents the remind set command
+var remindSetCmd = &cobra.Command{
+	Use:               "set [habit] [HH:MM]...",
+	Short:             "Set the daily reminder times for a habit",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		setReminders(args[0], args[1:])
+	},
+}
+
+// remindClearCmd represents the remind clear command
+var remindClearCmd = &cobra.Command{
+	Use:               "clear [habit]",
+	Short:             "Remove all reminder times from a habit",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeHabitKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		setReminders(args[0], nil)
+	},
+}
+
+// setReminders replaces a habit's reminder times and confirms the result
+func setReminders(habitKey string, times []string) {
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		fmt.Printf("Error loading habits: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := hm.SetReminders(habitKey, times); err != nil {
+		fmt.Printf("Error setting reminders: %v\n", err)
+		os.Exit(1)
+	}
+
+	activity, _ := hm.GetActivity(habitKey)
+	if len(activity.Reminders) == 0 {
+		fmt.Printf("✓ Cleared reminders for '%s'\n", activity.Name)
+	} else {
+		fmt.Printf("✓ Reminders for '%s' at %s\n", activity.Name, strings.Join(activity.Reminders, ", "))
+	}
+}
+
+// runReminderDaemon checks for due reminders until interrupted
+func runReminderDaemon() {
+	if remindInterval <= 0 {
+		fmt.Printf("Error: --interval must be positive, got %s\n", remindInterval)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reminder := internal.NewReminder(internal.SystemClock{}, internal.NewNotifier(remindCommand))
+
+	fmt.Println("Reminder daemon running. Press Ctrl+C to stop.")
+	if err := reminder.Run(ctx, remindInterval); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running reminders: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+	remindCmd.AddCommand(remindSetCmd)
+	remindCmd.AddCommand(remindClearCmd)
+
+	remindCmd.Flags().BoolVar(&remindDaemon, "daemon", false, "Run the reminder daemon in the foreground")
+	remindCmd.Flags().StringVar(&remindCommand, "command", "", "Shell command to run when desktop notifications are unavailable")
+	remindCmd.Flags().DurationVar(&remindInterval, "interval", 30*time.Second, "How often the daemon checks for due reminders")
+}
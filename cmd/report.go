@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+	"hab/ui"
+)
+
+var (
+	reportOut      string
+	reportTimeline string
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a self-contained HTML progress report",
+	Long: `Generate a single HTML page with a contribution heatmap, streak and
+completion statistics, and monthly summaries for every habit. The page has
+no external assets, so it can be emailed or hosted as-is.
+
+Examples:
+  hab report                          # Write report.html for the last 12 months
+  hab report --out progress.html -t 3m`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		timeline, err := parseTimeline(reportTimeline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		file, err := os.Create(reportOut)
+		if err != nil {
+			fmt.Printf("Error creating report: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		if err := ui.WriteHTMLReport(file, hm, timeline); err != nil {
+			fmt.Printf("Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Wrote report for %d habits to %s\n", len(hm.GetActivities()), reportOut)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportOut, "out", "o", "report.html", "File to write the report to")
+	reportCmd.Flags().StringVarP(&reportTimeline, "timeline", "t", "12m", "Timeline to cover (3m, 6m, 12m)")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	rmRange    string
+	rmWeekdays string
+	rmForce    bool
+	rmBy       string
+)
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:   "rm [habit] [date]",
+	Short: "Remove entries from a habit",
+	Long: `Remove an entry from a habit for a date, or one entry per day across a
+range. Dates accept the same forms as 'hab add'; if none is given, today's
+entry is removed. Days without an entry are skipped. The entries to remove
+are previewed and need confirmation unless --force is used.
+
+Examples:
+  hab rm exercise                            # Remove today's entry
+  hab rm exercise yesterday                  # Remove yesterday's entry
+  hab rm exercise --range 2025-03-01..2025-03-07
+  hab rm exercise --range 2025-03-01..2025-03-31 --weekdays sat,sun -f
+  hab rm watering --by alice                 # Remove alice's entry today`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeHabitKeyThenDate,
+	Run: func(cmd *cobra.Command, args []string) {
+		habitKey := args[0]
+		date := ""
+		if len(args) > 1 {
+			date = args[1]
+		}
+
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Check if habit exists
+		activity, exists := hm.GetActivity(habitKey)
+		if !exists {
+			fmt.Printf("Error: habit '%s' does not exist\n", habitKey)
+			os.Exit(1)
+		}
+
+		dates, err := resolveEntryDates(date, rmRange, rmWeekdays)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Only dates that actually have an entry can be removed, and with
+		// --by only those with an entry by that person
+		author := strings.TrimSpace(rmBy)
+		logged := make(map[string]bool)
+		for _, d := range activity.Dates {
+			logged[d] = author == "" || slices.Contains(activity.Authors[d], author)
+		}
+		var toRemove []string
+		for _, d := range dates {
+			if logged[d] {
+				toRemove = append(toRemove, d)
+			}
+		}
+
+		if len(toRemove) == 0 {
+			if author != "" {
+				fmt.Printf("No entries by %s for '%s' to remove\n", author, activity.Name)
+			} else {
+				fmt.Printf("No entries for '%s' to remove\n", activity.Name)
+			}
+			return
+		}
+
+		// Confirmation prompt unless --force is used
+		if !rmForce {
+			fmt.Printf("Removing %d entries from '%s':\n", len(toRemove), activity.Name)
+			printDatePreview(toRemove)
+			if !confirm("Continue?") {
+				fmt.Println("Operation cancelled")
+				return
+			}
+		}
+
+		// Remove the entries with a single save
+		removed, err := hm.RemoveEntries(habitKey, toRemove, author)
+		if err != nil {
+			fmt.Printf("Error removing entries: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(removed) == 1 {
+			fmt.Printf("✓ Removed entry for '%s' on %s\n", activity.Name, removed[0])
+		} else {
+			fmt.Printf("✓ Removed %d entries from '%s'\n", len(removed), activity.Name)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+
+	rmCmd.Flags().StringVarP(&rmRange, "range", "r", "", "Date range to remove entries from (START..END)")
+	rmCmd.Flags().StringVarP(&rmWeekdays, "weekdays", "w", "", "Only remove on these weekdays (e.g. mon,wed,fri)")
+	rmCmd.Flags().BoolVarP(&rmForce, "force", "f", false, "Remove without confirmation")
+	rmCmd.Flags().StringVar(&rmBy, "by", "", "Only remove entries logged by this person")
+}
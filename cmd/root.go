@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"hab/internal"
 	"hab/ui"
 )
 
@@ -12,6 +13,7 @@ var (
 	interactiveMode bool
 	timelineFlag    string
 	hideLegend      bool
+	profileFlag     string
 	version         = "dev"
 )
 
@@ -37,21 +39,23 @@ Examples:
   hab --no-legend        # Launch TUI without legend
   hab new exercise       # Create a new habit called 'exercise'
   hab exercise           # Add an entry for 'exercise' today
-  hab list               # List all habits with statistics`,
+  hab exercise yesterday # Add an entry for 'exercise' yesterday
+  hab list               # List all habits with statistics
+  hab --profile work     # Use the 'work' profile's habits`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		useProfileFlag()
+		if err := internal.CheckActiveProfile(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// If no arguments provided, or -i flag used, launch TUI
 		if len(args) == 0 || interactiveMode {
 			// Parse timeline flag
-			var timeline ui.TimelineDays
-			switch timelineFlag {
-			case "3m", "3":
-				timeline = ui.Timeline3Months
-			case "6m", "6":
-				timeline = ui.Timeline6Months
-			case "12m", "1y", "y", "12", "":
-				timeline = ui.Timeline12Months
-			default:
-				fmt.Fprintf(os.Stderr, "Invalid timeline '%s'. Use 3m, 6m, or 12m\n", timelineFlag)
+			timeline, err := parseTimeline(timelineFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
 			ui.RunTUIWithOptions(timeline, !hideLegend)
@@ -64,17 +68,47 @@ Examples:
 			return
 		}
 
+		// Handle habit and date - treat as "add entry for habit on date"
+		if len(args) == 2 {
+			addEntry(args[0], args[1])
+			return
+		}
+
 		// Show help for invalid usage
 		cmd.Help()
 	},
 	// Custom command validation to handle habit names
 	Args: cobra.ArbitraryArgs,
+	// Complete habit keys, then dates, for the 'hab [habit] [date]' shortcut
+	ValidArgsFunction: completeHabitKeyThenDate,
 	// Disable unknown command suggestions to allow habit names as arguments
 	DisableSuggestions: true,
 }
 
+// useProfileFlag selects the profile given with --profile, if any
+func useProfileFlag() {
+	if profileFlag != "" {
+		internal.UseProfile(profileFlag)
+	}
+}
+
+// parseTimeline converts a timeline flag value such as "3m" into days
+func parseTimeline(value string) (ui.TimelineDays, error) {
+	switch value {
+	case "3m", "3":
+		return ui.Timeline3Months, nil
+	case "6m", "6":
+		return ui.Timeline6Months, nil
+	case "12m", "1y", "y", "12", "":
+		return ui.Timeline12Months, nil
+	default:
+		return 0, fmt.Errorf("Invalid timeline '%s'. Use 3m, 6m, or 12m", value)
+	}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	addCompletionInstall()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -82,6 +116,10 @@ func Execute() {
 }
 
 func init() {
+	// Select a profile for any command
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Profile to use (see 'hab profile')")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfileName)
+
 	// Add the interactive flag
 	rootCmd.Flags().BoolVarP(&interactiveMode, "interactive", "i", false, "Launch interactive TUI mode")
 	
@@ -90,4 +128,7 @@ func init() {
 	
 	// Add legend visibility flag
 	rootCmd.Flags().BoolVar(&hideLegend, "no-legend", false, "Hide the completion legend")
+
+	// Attribute 'hab [habit]' entries like 'hab add --by'
+	rootCmd.Flags().StringVar(&addBy, "by", "", "Who logged the entry, for shared habits")
 }
\ No newline at end of file
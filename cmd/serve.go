@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve habits over a local JSON HTTP API",
+	Long: `Start an HTTP server exposing your habits as a JSON API, so dashboards,
+launcher scripts and phone shortcuts can log entries.
+
+Set a bearer token with --token or HAB_API_TOKEN to require clients to send
+"Authorization: Bearer <token>". Always set one when listening beyond localhost.
+
+Endpoints:
+  GET    /habits                      List habits with statistics
+  POST   /habits                      Create a habit {"key","name","color","target_per_day"}
+  GET    /habits/{key}                Show a habit
+  PATCH  /habits/{key}                Update a habit {"name","color","target_per_day"}
+  DELETE /habits/{key}                Delete a habit
+  GET    /habits/{key}/stats          Show statistics for a habit
+  POST   /habits/{key}/entries        Add an entry {"date"} (defaults to today)
+  DELETE /habits/{key}/entries/{date} Remove an entry
+
+Examples:
+  hab serve                             # Listen on 127.0.0.1:8765
+  hab serve --addr 0.0.0.0:8765 --token s3cret`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		token := serveToken
+		if token == "" {
+			token = os.Getenv("HAB_API_TOKEN")
+		}
+
+		server := internal.NewServer(token)
+
+		fmt.Printf("Serving habits on http://%s\n", serveAddr)
+		if token == "" {
+			fmt.Println("Warning: no token set, the API is open to anyone who can reach it")
+		}
+		if err := http.ListenAndServe(serveAddr, server.Handler()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running server: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8765", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token clients must send (defaults to HAB_API_TOKEN)")
+}
@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"hab/internal"
+	"hab/ui"
 )
 
+// trendDays is how many days of trends the sparklines show
+const trendDays = 30
+
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
 	Use:   "stats [habit]",
@@ -18,7 +23,8 @@ unique days, current streak, and other metrics.
 
 Examples:
   hab stats exercise    # Show stats for exercise habit`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeHabitKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		habitKey := args[0]
 
@@ -46,9 +52,14 @@ Examples:
 		fmt.Println(strings.Repeat("=", len(activity.Name)+16))
 		fmt.Printf("Key: %s\n", habitKey)
 		fmt.Printf("Color: %s\n", activity.Color)
-		fmt.Printf("Target per day: %d\n", stats["target_per_day"])
-		fmt.Printf("Total entries: %d\n", stats["total_entries"])
-		fmt.Printf("Unique days tracked: %d\n", stats["unique_days"])
+		if activity.IsAvoid() {
+			fmt.Println("Type: avoid (days without entries count as success)")
+			fmt.Printf("Slips: %d on %d days\n", stats["total_entries"], stats["unique_days"])
+		} else {
+			fmt.Printf("Target per day: %d\n", stats["target_per_day"])
+			fmt.Printf("Total entries: %d\n", stats["total_entries"])
+			fmt.Printf("Unique days tracked: %d\n", stats["unique_days"])
+		}
 		
 		if streak, ok := stats["current_streak"].(int); ok {
 			if streak > 0 {
@@ -58,8 +69,15 @@ Examples:
 			}
 		}
 
+		// Habits to avoid succeed on every tracked day without a slip
+		if tracked, ok := stats["tracked_days"].(int); ok && tracked > 0 {
+			clean := stats["clean_days"].(int)
+			fmt.Printf("Clean days: %.1f%% (%d/%d days since %s)\n",
+				float64(clean)/float64(tracked)*100, clean, tracked, activity.TrackingStart())
+		}
+
 		// Calculate success rate for multi-frequency habits
-		if targetPerDay, ok := stats["target_per_day"].(int); ok && targetPerDay > 1 {
+		if targetPerDay, ok := stats["target_per_day"].(int); ok && targetPerDay > 1 && !activity.IsAvoid() {
 			totalEntries := stats["total_entries"].(int)
 			uniqueDays := stats["unique_days"].(int)
 			expectedEntries := uniqueDays * targetPerDay
@@ -70,7 +88,34 @@ Examples:
 			}
 		}
 
-		fmt.Printf("\nUse 'hab %s' to add an entry for today\n", habitKey)
+		// Strength and rolling rates over the last month, which a single
+		// miss only dents
+		if trends := hm.Trends(habitKey, time.Now()); trends.Len() > 0 {
+			last := trends.Len() - 1
+			fmt.Printf("\nTrends, last %d days:\n", min(trends.Len(), trendDays))
+			fmt.Printf("  Strength     %3.0f%%  %s\n", trends.Strength[last]*100, ui.Sparkline(trends.Strength, trendDays))
+			fmt.Printf("  7-day rate   %3.0f%%  %s\n", trends.Rolling7[last]*100, ui.Sparkline(trends.Rolling7, trendDays))
+			fmt.Printf("  30-day rate  %3.0f%%  %s\n", trends.Rolling30[last]*100, ui.Sparkline(trends.Rolling30, trendDays))
+		}
+
+		// Break shared habits down by who logged the entries
+		if len(activity.Authors) > 0 {
+			fmt.Println("\nBy person:")
+			for _, c := range internal.Contributions(activity) {
+				author := c.Author
+				if author == "" {
+					author = "(unattributed)"
+				}
+				fmt.Printf("  %-16s %d entries on %d days (%.0f%%)\n", author, c.Entries, c.Days,
+					float64(c.Entries)/float64(len(activity.Dates))*100)
+			}
+		}
+
+		if activity.IsAvoid() {
+			fmt.Printf("\nUse 'hab %s' to log a slip today\n", habitKey)
+		} else {
+			fmt.Printf("\nUse 'hab %s' to add an entry for today\n", habitKey)
+		}
 	},
 }
 
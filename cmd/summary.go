@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+var (
+	summaryWeek     bool
+	summaryMonth    bool
+	summaryDate     string
+	summaryMarkdown bool
+)
+
+// trendArrows shows each trend at a glance
+var trendArrows = map[string]string{
+	internal.TrendUp:     "↑",
+	internal.TrendDown:   "↓",
+	internal.TrendSteady: "→",
+	internal.TrendNew:    "new",
+}
+
+// summaryCmd represents the summary command
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarise a week or month of habits against the one before",
+	Long: `Show each habit's completion for a week (Monday to Sunday) or month next to
+the previous one, with trend arrows, the best and worst habits and the total
+check-ins. Completion is the share of days the daily target was met, or
+days without slips for habits to avoid, counting only days from when the
+habit was first tracked up to today.
+
+--date picks the period containing that date instead of the current one.
+--markdown prints a table to paste into notes or retros.
+
+Examples:
+  hab summary                      # This week
+  hab summary --month              # This month
+  hab summary --date 1w            # Last week
+  hab summary --month --date 2025-03-01 --markdown`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		date, err := internal.ParseDate(summaryDate, now)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		kind := internal.PeriodWeek
+		if summaryMonth {
+			kind = internal.PeriodMonth
+		}
+
+		summary, err := hm.SummarisePeriod(kind, date, now)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if summaryMarkdown {
+			printSummaryMarkdown(summary)
+		} else {
+			printSummary(summary)
+		}
+	},
+}
+
+// printSummary prints a period summary as a plain table
+func printSummary(summary internal.PeriodSummary) {
+	title := fmt.Sprintf("%s summary: %s (%s to %s)", strings.Title(summary.Period.Kind), summary.Period.Label(),
+		summary.Period.Start.Format(internal.DateFormat), summary.Period.End.Format(internal.DateFormat))
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", len(title)))
+
+	if len(summary.Habits) == 0 {
+		fmt.Printf("\nNo habits were tracked in %s or %s\n", summary.Period.Label(), summary.Previous.Label())
+		return
+	}
+
+	// Size the columns to fit the longest name and period label
+	nameWidth := len("Habit")
+	for _, habit := range summary.Habits {
+		nameWidth = max(nameWidth, len(habit.Activity.Name))
+	}
+	rateWidth := max(len(summary.Period.Label()), len(summary.Previous.Label()), len("31/31 100%"))
+
+	fmt.Println()
+	fmt.Printf("%-*s  %*s  %*s  %s\n", nameWidth, "Habit", rateWidth, summary.Period.Label(),
+		rateWidth, summary.Previous.Label(), "Trend")
+	for _, habit := range summary.Habits {
+		fmt.Printf("%-*s  %*s  %*s  %s\n", nameWidth, habit.Activity.Name,
+			rateWidth, periodRate(habit.Current), rateWidth, periodRate(habit.Previous), trendText(habit))
+	}
+
+	fmt.Println()
+	fmt.Printf("Check-ins: %d (%d in %s)\n", summary.CheckIns, summary.PreviousCheckIns, summary.Previous.Label())
+	if summary.Slips > 0 || summary.PreviousSlips > 0 {
+		fmt.Printf("Slips: %d (%d in %s)\n", summary.Slips, summary.PreviousSlips, summary.Previous.Label())
+	}
+	if best, ok := summary.Best(); ok {
+		fmt.Printf("Best: %s (%.0f%%)\n", best.Activity.Name, best.Current.Rate()*100)
+	}
+	if worst, ok := summary.Worst(); ok && len(summary.Habits) > 1 {
+		fmt.Printf("Worst: %s (%.0f%%)\n", worst.Activity.Name, worst.Current.Rate()*100)
+	}
+}
+
+// printSummaryMarkdown prints a period summary as Markdown
+func printSummaryMarkdown(summary internal.PeriodSummary) {
+	fmt.Printf("## Habits: %s %s (%s to %s)\n\n", summary.Period.Kind, summary.Period.Label(),
+		summary.Period.Start.Format(internal.DateFormat), summary.Period.End.Format(internal.DateFormat))
+
+	if len(summary.Habits) == 0 {
+		fmt.Printf("No habits were tracked in %s or %s.\n", summary.Period.Label(), summary.Previous.Label())
+		return
+	}
+
+	fmt.Printf("| Habit | %s | %s | Trend |\n", summary.Period.Label(), summary.Previous.Label())
+	fmt.Println("|---|---:|---:|:---:|")
+	for _, habit := range summary.Habits {
+		fmt.Printf("| %s | %s | %s | %s |\n", strings.ReplaceAll(habit.Activity.Name, "|", `\|`),
+			periodRate(habit.Current), periodRate(habit.Previous), trendText(habit))
+	}
+
+	fmt.Println()
+	fmt.Printf("- **Check-ins:** %d (%d in %s)\n", summary.CheckIns, summary.PreviousCheckIns, summary.Previous.Label())
+	if summary.Slips > 0 || summary.PreviousSlips > 0 {
+		fmt.Printf("- **Slips:** %d (%d in %s)\n", summary.Slips, summary.PreviousSlips, summary.Previous.Label())
+	}
+	if best, ok := summary.Best(); ok {
+		fmt.Printf("- **Best:** %s (%.0f%%)\n", best.Activity.Name, best.Current.Rate()*100)
+	}
+	if worst, ok := summary.Worst(); ok && len(summary.Habits) > 1 {
+		fmt.Printf("- **Worst:** %s (%.0f%%)\n", worst.Activity.Name, worst.Current.Rate()*100)
+	}
+}
+
+// periodRate formats the days met out of the days tracked in a period
+func periodRate(hp internal.HabitPeriod) string {
+	if hp.Days == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d %.0f%%", hp.DaysMet, hp.Days, hp.Rate()*100)
+}
+
+// trendText shows a habit's trend arrow and the change in percentage points
+func trendText(habit internal.HabitSummary) string {
+	trend := habit.Trend()
+	if trend == internal.TrendNew {
+		return trendArrows[trend]
+	}
+	return fmt.Sprintf("%s %+.0f pts", trendArrows[trend], habit.Change()*100)
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+
+	summaryCmd.Flags().BoolVar(&summaryWeek, "week", false, "Summarise a week, Monday to Sunday (default)")
+	summaryCmd.Flags().BoolVar(&summaryMonth, "month", false, "Summarise a month")
+	summaryCmd.Flags().StringVar(&summaryDate, "date", "", "A date in the period to summarise (default today)")
+	summaryCmd.Flags().BoolVar(&summaryMarkdown, "markdown", false, "Print Markdown for notes and retros")
+	summaryCmd.MarkFlagsMutuallyExclusive("week", "month")
+	summaryCmd.RegisterFlagCompletionFunc("date", completeDate)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hab/internal"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync your habits with other machines through git",
+	Long: `Commit your habits to a git repository, merge in changes made on other
+machines, and push the result. Run 'hab sync init <remote>' once per machine
+first; the remote can be a bare repository on a local or mounted path, or
+any URL git can push to.
+
+Habits are exported as JSON whatever the storage backend. When two machines
+changed habits since they last synced, the copies are merged by habit rather
+than by line, using the last synced copy as the common ancestor (see
+'hab merge --help' for the rules), so the file never ends up with conflict
+markers.
+
+Examples:
+  hab sync init /mnt/nas/habits.git   # Set up (creates the bare repo if missing)
+  hab sync init git@example.com:me/habits.git
+  hab sync                            # Commit, pull, merge and push`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		syncer := internal.NewSyncer()
+		result, err := syncer.Sync(hm)
+		if err != nil {
+			fmt.Printf("Error syncing: %v\n", err)
+			os.Exit(1)
+		}
+
+		printSyncResult(syncer, result)
+	},
+}
+
+// syncInitCmd sets up the sync repository
+var syncInitCmd = &cobra.Command{
+	Use:   "init <remote>",
+	Short: "Set up syncing with a git remote",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		hm := internal.NewHabitManager()
+		if err := hm.Load(); err != nil {
+			fmt.Printf("Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+
+		syncer := internal.NewSyncer()
+		result, err := syncer.Init(hm, args[0])
+		if err != nil {
+			fmt.Printf("Error setting up sync: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Sync set up in %s\n", syncer.Dir())
+		printSyncResult(syncer, result)
+	},
+}
+
+// printSyncResult summarises what a sync did
+func printSyncResult(syncer *internal.Syncer, result internal.SyncResult) {
+	remote, _ := syncer.Remote()
+
+	switch {
+	case result.Merged:
+		fmt.Println("✓ Merged changes from other machines")
+	case result.Pulled:
+		fmt.Println("✓ Pulled changes from other machines")
+	}
+	if result.Updated {
+		fmt.Println("  Your habits were updated")
+	}
+	printMergeConflicts(result.Conflicts)
+
+	if result.Pushed {
+		fmt.Printf("✓ Pushed to %s\n", remote)
+	} else if !result.Pulled && !result.Merged {
+		fmt.Printf("Already in sync with %s\n", remote)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncInitCmd)
+}
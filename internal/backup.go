@@ -0,0 +1,310 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupIDFormat timestamps backups. Backups made within the same second
+// get a "-2", "-3"... suffix, see backupSequence.
+const backupIDFormat = "20060102-150405"
+
+// BackupRetention says which backups to keep: the most recent KeepLast,
+// plus the newest backup of each of the last Daily days, Weekly weeks and
+// Monthly months that have one
+type BackupRetention struct {
+	KeepLast int `json:"keep_last"`
+	Daily    int `json:"daily"`
+	Weekly   int `json:"weekly"`
+	Monthly  int `json:"monthly"`
+}
+
+// DefaultBackupRetention is used when the config file doesn't set one
+var DefaultBackupRetention = BackupRetention{KeepLast: 10, Daily: 7, Weekly: 4, Monthly: 12}
+
+// IsDisabled reports whether the retention keeps no backups at all
+func (r BackupRetention) IsDisabled() bool {
+	return r.KeepLast <= 0 && r.Daily <= 0 && r.Weekly <= 0 && r.Monthly <= 0
+}
+
+// Backup is a copy of the data file taken before it was overwritten
+type Backup struct {
+	ID   string
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// backupDir returns where backups of a data file are kept
+func backupDir(dataFile string) string {
+	return filepath.Join(filepath.Dir(dataFile), "backups")
+}
+
+// backupName splits a data file name into the prefix and extension its
+// backups are named with, e.g. "activities-" and ".json"
+func backupName(dataFile string) (string, string) {
+	ext := filepath.Ext(dataFile)
+	return strings.TrimSuffix(filepath.Base(dataFile), ext) + "-", ext
+}
+
+// ListBackups returns the backups of a data file, newest first
+func ListBackups(dataFile string) ([]Backup, error) {
+	entries, err := os.ReadDir(backupDir(dataFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups: %w", err)
+	}
+
+	prefix, ext := backupName(dataFile)
+	var backups []Backup
+	for _, entry := range entries {
+		id, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || !strings.HasSuffix(id, ext) {
+			continue
+		}
+		id = strings.TrimSuffix(id, ext)
+
+		if len(id) < len(backupIDFormat) {
+			continue
+		}
+		created, err := time.ParseInLocation(backupIDFormat, id[:len(backupIDFormat)], time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{
+			ID:   id,
+			Path: filepath.Join(backupDir(dataFile), entry.Name()),
+			Time: created,
+			Size: info.Size(),
+		})
+	}
+
+	// Compare suffixes as numbers so "-10" is newer than "-9"
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Time.Equal(backups[j].Time) {
+			return backups[i].Time.After(backups[j].Time)
+		}
+		return backupSequence(backups[i].ID) > backupSequence(backups[j].ID)
+	})
+	return backups, nil
+}
+
+// backupSequence returns the position of a backup among those made in the
+// same second: 1 for the first, then the number in its suffix
+func backupSequence(id string) int {
+	suffix, ok := strings.CutPrefix(id[len(backupIDFormat):], "-")
+	if !ok {
+		return 1
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// FindBackup returns the backup of a data file with the given ID
+func FindBackup(dataFile, id string) (Backup, error) {
+	backups, err := ListBackups(dataFile)
+	if err != nil {
+		return Backup{}, err
+	}
+	for _, backup := range backups {
+		if backup.ID == id {
+			return backup, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("backup '%s' not found, see 'hab backup list'", id)
+}
+
+// CreateBackup copies the data file into the backup directory. Nothing is
+// done if the file doesn't exist yet.
+func CreateBackup(dataFile string, now time.Time) error {
+	source, err := os.Open(dataFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(backupDir(dataFile), 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	prefix, ext := backupName(dataFile)
+	id := now.Format(backupIDFormat)
+	var path string
+	var target *os.File
+	for n := 2; ; n++ {
+		path = filepath.Join(backupDir(dataFile), prefix+id+ext)
+		target, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if !os.IsExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format(backupIDFormat), n)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if _, err := io.Copy(target, source); err != nil {
+		target.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return target.Close()
+}
+
+// PruneBackups deletes the backups the retention doesn't keep
+func PruneBackups(dataFile string, retention BackupRetention) error {
+	backups, err := ListBackups(dataFile)
+	if err != nil {
+		return err
+	}
+
+	keep := retainedBackups(backups, retention)
+	for _, backup := range backups {
+		if !keep[backup.ID] {
+			if err := os.Remove(backup.Path); err != nil {
+				return fmt.Errorf("failed to remove old backup: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// retainedBackups picks the IDs to keep from backups sorted newest first
+func retainedBackups(backups []Backup, retention BackupRetention) map[string]bool {
+	keep := make(map[string]bool)
+	for i := 0; i < len(backups) && i < retention.KeepLast; i++ {
+		keep[backups[i].ID] = true
+	}
+
+	periods := []struct {
+		limit  int
+		period func(time.Time) string
+	}{
+		{retention.Daily, func(t time.Time) string { return t.Format(DateFormat) }},
+		{retention.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{retention.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, p := range periods {
+		seen := make(map[string]bool)
+		for _, backup := range backups {
+			if len(seen) >= p.limit {
+				break
+			}
+			if key := p.period(backup.Time); !seen[key] {
+				seen[key] = true
+				keep[backup.ID] = true
+			}
+		}
+	}
+	return keep
+}
+
+// backup saves a copy of the data file before it's overwritten and prunes
+// old copies
+func (hm *HabitManager) backup() error {
+	if hm.retention.IsDisabled() {
+		return nil
+	}
+	if err := CreateBackup(hm.DataFile(), time.Now()); err != nil {
+		return err
+	}
+	return PruneBackups(hm.DataFile(), hm.retention)
+}
+
+// LoadBackup reads the habits stored in a backup without changing it
+func (hm *HabitManager) LoadBackup(backup Backup) (*ActivitiesData, error) {
+	if filepath.Ext(backup.Path) == ".db" {
+		return NewSQLiteStore(backup.Path).Load()
+	}
+
+	contents, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	return ParseActivitiesData(contents)
+}
+
+// RestoreBackup replaces the data file with a backup. The current file is
+// backed up first, so a restore can itself be undone.
+func (hm *HabitManager) RestoreBackup(backup Backup) error {
+	if err := CreateBackup(hm.DataFile(), time.Now()); err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := os.WriteFile(hm.DataFile(), contents, 0644); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+
+	return hm.Load()
+}
+
+// HabitDiff describes how one habit differs between two copies of the data
+type HabitDiff struct {
+	Key         string
+	Name        string
+	FromEntries int
+	ToEntries   int
+	Added       bool // only in the new copy
+	Removed     bool // only in the old copy
+}
+
+// DiffData lists the habits whose presence or entry count differs between
+// from and to, in key order
+func DiffData(from, to *ActivitiesData) []HabitDiff {
+	keys := make(map[string]bool)
+	for key := range from.Activities {
+		keys[key] = true
+	}
+	for key := range to.Activities {
+		keys[key] = true
+	}
+
+	var diffs []HabitDiff
+	for _, key := range sortedSet(keys) {
+		fromActivity, inFrom := from.Activities[key]
+		toActivity, inTo := to.Activities[key]
+
+		diff := HabitDiff{
+			Key:         key,
+			Name:        toActivity.Name,
+			FromEntries: len(fromActivity.Dates),
+			ToEntries:   len(toActivity.Dates),
+			Added:       !inFrom,
+			Removed:     !inTo,
+		}
+		if !inTo {
+			diff.Name = fromActivity.Name
+		}
+		if diff.Added || diff.Removed || diff.FromEntries != diff.ToEntries {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
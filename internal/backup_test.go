@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSameSecondBackupsNewestFirst(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "activities.json")
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.Local)
+
+	// A backup from a second earlier, then twelve within the same second
+	if err := os.WriteFile(dataFile, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateBackup(dataFile, now.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 12; i++ {
+		if err := CreateBackup(dataFile, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := ListBackups(dataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"20240304-120000-12", "20240304-120000-11", "20240304-120000-10", "20240304-120000-9"}
+	for i, id := range want {
+		if backups[i].ID != id {
+			t.Errorf("backup %d = %s, want %s", i, backups[i].ID, id)
+		}
+	}
+	if first, last := backups[len(backups)-2].ID, backups[len(backups)-1].ID; first != "20240304-120000" || last != "20240304-115959" {
+		t.Errorf("oldest backups = %s, %s, want 20240304-120000, 20240304-115959", first, last)
+	}
+
+	keep := retainedBackups(backups, BackupRetention{KeepLast: 1})
+	if len(keep) != 1 || !keep["20240304-120000-12"] {
+		t.Errorf("KeepLast 1 keeps %v, want the newest backup", keep)
+	}
+}
+
+func TestBackupSequence(t *testing.T) {
+	for id, want := range map[string]int{
+		"20240304-120000":    1,
+		"20240304-120000-2":  2,
+		"20240304-120000-10": 10,
+		"20240304-120000-x":  1,
+	} {
+		if got := backupSequence(id); got != want {
+			t.Errorf("backupSequence(%s) = %d, want %d", id, got, want)
+		}
+	}
+}
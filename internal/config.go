@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user settings that apply across commands
+type Config struct {
+	Profile           string `json:"profile,omitempty"`            // profile used by default; only read from the main config
+	Storage           string `json:"storage,omitempty"`            // storage backend: json (default) or sqlite
+	PassphraseCommand string `json:"passphrase_command,omitempty"` // prints the data file passphrase
+
+	Backups *BackupRetention `json:"backups,omitempty"` // which automatic backups to keep
+}
+
+// BackupRetention returns the configured backup retention or the default
+func (c *Config) BackupRetention() BackupRetention {
+	if c.Backups == nil {
+		return DefaultBackupRetention
+	}
+	return *c.Backups
+}
+
+// ConfigPath returns the active profile's config file path, which
+// HAB_CONFIG_FILE overrides
+func ConfigPath() string {
+	if configFile := os.Getenv("HAB_CONFIG_FILE"); configFile != "" {
+		return configFile
+	}
+	return filepath.Join(profileDir(ActiveProfile()), "config.json")
+}
+
+// mainConfigPath returns the default profile's config file, which also
+// remembers the profile to use
+func mainConfigPath() string {
+	if configFile := os.Getenv("HAB_CONFIG_FILE"); configFile != "" {
+		return configFile
+	}
+	return filepath.Join(profileDir(DefaultProfile), "config.json")
+}
+
+// LoadConfig reads the active profile's config file. A missing file gives
+// the defaults.
+func LoadConfig() (*Config, error) {
+	return readConfig(ConfigPath())
+}
+
+// readConfig reads a config file, giving the defaults if it's missing
+func readConfig(path string) (*Config, error) {
+	config := &Config{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return config, nil
+}
+
+// Save writes the active profile's config file
+func (c *Config) Save() error {
+	return c.writeTo(ConfigPath())
+}
+
+// writeTo writes the config to a file
+func (c *Config) writeTo(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
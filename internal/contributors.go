@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"sort"
+)
+
+// Contribution is how many of a habit's entries one person logged
+type Contribution struct {
+	Author  string // empty for unattributed entries
+	Entries int
+	Days    int
+}
+
+// Contributions breaks a habit's entries down by author, most entries
+// first, with unattributed entries last
+func Contributions(activity Activity) []Contribution {
+	entries := make(map[string]int)
+	days := make(map[string]map[string]bool)
+	count := func(author, date string) {
+		entries[author]++
+		if days[author] == nil {
+			days[author] = make(map[string]bool)
+		}
+		days[author][date] = true
+	}
+
+	for date, total := range NewDateCounts(activity.Dates) {
+		authors := activity.Authors[date]
+		for _, author := range authors {
+			count(author, date)
+		}
+		for i := len(authors); i < total; i++ {
+			count("", date)
+		}
+	}
+
+	contributions := make([]Contribution, 0, len(entries))
+	for author, n := range entries {
+		contributions = append(contributions, Contribution{Author: author, Entries: n, Days: len(days[author])})
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		a, b := contributions[i], contributions[j]
+		if (a.Author == "") != (b.Author == "") {
+			return b.Author == ""
+		}
+		if a.Entries != b.Entries {
+			return a.Entries > b.Entries
+		}
+		return a.Author < b.Author
+	})
+	return contributions
+}
+
+// TopContributor returns who logged most of a habit's entries on a date,
+// the alphabetically first on a tie, or "" if none of them are attributed
+func TopContributor(activity Activity, date string) string {
+	counts := make(map[string]int)
+	top := ""
+	for _, author := range activity.Authors[date] {
+		counts[author]++
+		if counts[author] > counts[top] || (counts[author] == counts[top] && author < top) {
+			top = author
+		}
+	}
+	return top
+}
+
+// Authors returns everyone who logged an entry for any of the habits, in
+// alphabetical order
+func Authors(activities map[string]Activity) []string {
+	seen := make(map[string]bool)
+	for _, activity := range activities {
+		for _, authors := range activity.Authors {
+			for _, author := range authors {
+				seen[author] = true
+			}
+		}
+	}
+	return sortedSet(seen)
+}
+
+// cloneAuthors returns a deep copy of an activity's authors
+func cloneAuthors(authors map[string][]string) map[string][]string {
+	if authors == nil {
+		return nil
+	}
+	copied := make(map[string][]string, len(authors))
+	for date, names := range authors {
+		copied[date] = append([]string(nil), names...)
+	}
+	return copied
+}
+
+// trimAuthors drops authors of a date beyond its number of entries, so
+// removing an entry never leaves a name behind
+func (a *Activity) trimAuthors(date string) {
+	authors, exists := a.Authors[date]
+	if !exists {
+		return
+	}
+
+	count := 0
+	for _, d := range a.Dates {
+		if d == date {
+			count++
+		}
+	}
+
+	if count < len(authors) {
+		authors = authors[:count]
+	}
+	if len(authors) == 0 {
+		delete(a.Authors, date)
+	} else {
+		a.Authors[date] = authors
+	}
+	if len(a.Authors) == 0 {
+		a.Authors = nil
+	}
+}
+
+// trimAllAuthors applies trimAuthors to every date with authors
+func (a *Activity) trimAllAuthors() {
+	for date := range a.Authors {
+		a.trimAuthors(date)
+	}
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContributions(t *testing.T) {
+	activity := Activity{
+		Dates: []string{"2025-01-01", "2025-01-01", "2025-01-01", "2025-01-02", "2025-01-03", "2025-01-03", "2025-01-04"},
+		Authors: map[string][]string{
+			"2025-01-01": {"carol", "bob"}, // the third entry is unattributed
+			"2025-01-02": {"bob"},
+			"2025-01-04": {"alice"},
+		},
+	}
+
+	want := []Contribution{
+		{Author: "bob", Entries: 2, Days: 2},
+		{Author: "alice", Entries: 1, Days: 1},
+		{Author: "carol", Entries: 1, Days: 1},
+		{Author: "", Entries: 3, Days: 2},
+	}
+	if got := Contributions(activity); !slices.Equal(got, want) {
+		t.Errorf("Contributions = %+v, want %+v", got, want)
+	}
+
+	if got := Contributions(Activity{}); len(got) != 0 {
+		t.Errorf("Contributions of a habit without entries = %+v", got)
+	}
+}
+
+func TestTopContributor(t *testing.T) {
+	activity := Activity{Authors: map[string][]string{
+		"2025-01-01": {"bob", "alice", "bob"},
+		"2025-01-02": {"bob", "alice"},
+	}}
+
+	for date, want := range map[string]string{
+		"2025-01-01": "bob",
+		"2025-01-02": "alice", // a tie goes to the alphabetically first
+		"2025-01-03": "",
+	} {
+		if got := TopContributor(activity, date); got != want {
+			t.Errorf("TopContributor on %s = %q, want %q", date, got, want)
+		}
+	}
+
+	want := []string{"alice", "bob"}
+	if got := Authors(map[string]Activity{"a": activity, "b": {}}); !slices.Equal(got, want) {
+		t.Errorf("Authors = %v, want %v", got, want)
+	}
+}
+
+func TestTrimAuthors(t *testing.T) {
+	activity := Activity{
+		Dates:   []string{"2025-01-01", "2025-01-02"},
+		Authors: map[string][]string{"2025-01-01": {"alice", "bob"}, "2025-01-03": {"carol"}},
+	}
+
+	activity.trimAuthors("2025-01-01")
+	if got := activity.Authors["2025-01-01"]; !slices.Equal(got, []string{"alice"}) {
+		t.Errorf("authors of a date with one entry = %v, want [alice]", got)
+	}
+
+	activity.trimAllAuthors()
+	if _, exists := activity.Authors["2025-01-03"]; exists {
+		t.Errorf("authors of a date without entries were kept")
+	}
+
+	activity.Dates = nil
+	activity.trimAllAuthors()
+	if activity.Authors != nil {
+		t.Errorf("authors = %v, want nil once no date has any", activity.Authors)
+	}
+}
+
+func TestRemoveEntriesWithAuthors(t *testing.T) {
+	// Three entries on a day, the last of them unattributed
+	setup := func(t *testing.T) *HabitManager {
+		hm := newTestManager(t)
+		err := hm.Mutate(func(tx *Tx) error {
+			for _, author := range []string{"bob", ""} {
+				if err := tx.AddEntryBy("exercise", "2025-01-01", author); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hm
+	}
+
+	for _, test := range []struct {
+		name    string
+		removes []string // authors removing an entry each, "" without --by
+		removed int
+		count   int
+		authors []string
+	}{
+		{"without --by removes the unattributed entry first", []string{""}, 1, 2, []string{"alice", "bob"}},
+		{"without --by then removes the latest attributed entry", []string{"", ""}, 2, 1, []string{"alice"}},
+		{"with --by removes that person's entry", []string{"alice"}, 1, 2, []string{"bob"}},
+		{"with --by keeps the unattributed entry", []string{"alice", "bob"}, 2, 1, nil},
+		{"with --by for someone without an entry does nothing", []string{"carol"}, 0, 3, []string{"alice", "bob"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			hm := setup(t)
+			removed := 0
+			for _, author := range test.removes {
+				dates, err := hm.RemoveEntries("exercise", []string{"2025-01-01"}, author)
+				if err != nil {
+					t.Fatal(err)
+				}
+				removed += len(dates)
+			}
+
+			activity, _ := hm.GetActivity("exercise")
+			if removed != test.removed || hm.CountOn("exercise", "2025-01-01") != test.count {
+				t.Errorf("removed %d leaving %d, want %d leaving %d", removed, hm.CountOn("exercise", "2025-01-01"), test.removed, test.count)
+			}
+			if got := activity.Authors["2025-01-01"]; !slices.Equal(got, test.authors) {
+				t.Errorf("authors = %v, want %v", got, test.authors)
+			}
+
+			unattributed := 0
+			for _, contribution := range Contributions(activity) {
+				if contribution.Author == "" {
+					unattributed = contribution.Entries
+				}
+			}
+			if want := test.count - len(test.authors); unattributed != want {
+				t.Errorf("unattributed entries = %d, want %d", unattributed, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// scryptWorkFactor is the log2 scrypt cost for passphrases. It is below
+// age's default so that every command doesn't pause for a second.
+const scryptWorkFactor = 16
+
+// ErrWrongKey is returned when encrypted data can't be opened with the
+// configured passphrase or key file
+var ErrWrongKey = errors.New("wrong passphrase or key file")
+
+// ErrNoKey is returned when data is encrypted but no passphrase or key file
+// is configured
+var ErrNoKey = errors.New("the data file is encrypted, set HAB_PASSPHRASE, HAB_KEY_FILE or HAB_PASSPHRASE_COMMAND")
+
+// Keyring looks up a stored passphrase
+type Keyring interface {
+	Passphrase() (string, error)
+}
+
+// CommandKeyring reads the passphrase from a command's output, standing in
+// for an OS keyring, e.g. "secret-tool lookup service hab",
+// "security find-generic-password -w -s hab" or "pass show hab"
+type CommandKeyring struct {
+	Command string
+}
+
+// Passphrase runs the command and returns the first line it prints
+func (k CommandKeyring) Passphrase() (string, error) {
+	output, err := shellCommand(k.Command).Output()
+	if err != nil {
+		return "", fmt.Errorf("passphrase command failed: %w", err)
+	}
+	passphrase, _, _ := strings.Cut(string(output), "\n")
+	passphrase = strings.TrimSuffix(passphrase, "\r")
+	if passphrase == "" {
+		return "", errors.New("passphrase command printed nothing")
+	}
+	return passphrase, nil
+}
+
+// Keys holds what encrypts and decrypts the data file: either a passphrase
+// or an age key file
+type Keys struct {
+	Passphrase string
+	KeyFile    string
+}
+
+// LoadKeys finds the configured key. HAB_KEY_FILE is used first, then
+// HAB_PASSPHRASE, then the passphrase command from HAB_PASSPHRASE_COMMAND or
+// the config file. It returns ErrNoKey when none is set.
+func LoadKeys() (*Keys, error) {
+	if keyFile := os.Getenv("HAB_KEY_FILE"); keyFile != "" {
+		return &Keys{KeyFile: keyFile}, nil
+	}
+	if passphrase := os.Getenv("HAB_PASSPHRASE"); passphrase != "" {
+		return &Keys{Passphrase: passphrase}, nil
+	}
+
+	command := os.Getenv("HAB_PASSPHRASE_COMMAND")
+	if command == "" {
+		config, err := LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		command = config.PassphraseCommand
+	}
+	if command == "" {
+		return nil, ErrNoKey
+	}
+
+	passphrase, err := CommandKeyring{Command: command}.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return &Keys{Passphrase: passphrase}, nil
+}
+
+// identity returns the age identity that decrypts with these keys
+func (k *Keys) identity() (age.Identity, error) {
+	if k.KeyFile == "" {
+		identity, err := age.NewScryptIdentity(k.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identity.SetMaxWorkFactor(22) // also open files made by the age tool
+		return identity, nil
+	}
+
+	file, err := os.Open(k.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	x25519, ok := identities[0].(*age.X25519Identity)
+	if !ok || len(identities) != 1 {
+		return nil, errors.New("key file must hold a single age X25519 key")
+	}
+	return x25519, nil
+}
+
+// recipient returns the age recipient that encrypts with these keys
+func (k *Keys) recipient() (age.Recipient, error) {
+	if k.KeyFile == "" {
+		recipient, err := age.NewScryptRecipient(k.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		recipient.SetWorkFactor(scryptWorkFactor)
+		return recipient, nil
+	}
+
+	identity, err := k.identity()
+	if err != nil {
+		return nil, err
+	}
+	return identity.(*age.X25519Identity).Recipient(), nil
+}
+
+// Encrypt encrypts plaintext into an ASCII-armored age file
+func Encrypt(plaintext []byte, keys *Keys) ([]byte, error) {
+	recipient, err := keys.recipient()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	armored := armor.NewWriter(&buf)
+	writer, err := age.Encrypt(armored, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := writer.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := armored.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt opens an age file made by Encrypt. It returns ErrWrongKey when
+// the keys don't match the ones it was encrypted with.
+func Decrypt(ciphertext []byte, keys *Keys) ([]byte, error) {
+	identity, err := keys.identity()
+	if err != nil {
+		return nil, err
+	}
+
+	var source io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armor.Header)) {
+		source = armor.NewReader(source)
+	}
+
+	reader, err := age.Decrypt(source, identity)
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return nil, ErrWrongKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether contents are an age file, armored or not
+func IsEncrypted(contents []byte) bool {
+	contents = bytes.TrimSpace(contents)
+	return bytes.HasPrefix(contents, []byte(armor.Header)) ||
+		bytes.HasPrefix(contents, []byte("age-encryption.org/"))
+}
+
+// IsEncryptedFile reports whether the file at path is encrypted. Missing
+// files are not.
+func IsEncryptedFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, 64)
+	n, _ := io.ReadFull(file, head)
+	return IsEncrypted(head[:n])
+}
+
+// GenerateKeyFile writes a new age key file readable only by its owner
+func GenerateKeyFile(path string) error {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	contents := fmt.Sprintf("# public key: %s\n%s\n", identity.Recipient(), identity)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(contents); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+// EncryptFile encrypts a plaintext data file in place
+func EncryptFile(path string, keys *Keys) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+	if IsEncrypted(contents) {
+		return fmt.Errorf("%s is already encrypted", path)
+	}
+	if _, err := ParseActivitiesData(contents); err != nil {
+		return err
+	}
+
+	encrypted, err := Encrypt(contents, keys)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the file can be opened again before replacing it
+	if decrypted, err := Decrypt(encrypted, keys); err != nil || !bytes.Equal(decrypted, contents) {
+		return errors.New("encryption check failed, the file was not changed")
+	}
+
+	if err := os.WriteFile(path, encrypted, 0600); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+	return os.Chmod(path, 0600)
+}
+
+// DecryptFile decrypts an encrypted data file in place
+func DecryptFile(path string, keys *Keys) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+	if !IsEncrypted(contents) {
+		return fmt.Errorf("%s is not encrypted", path)
+	}
+
+	decrypted, err := Decrypt(contents, keys)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, decrypted, 0644); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+	return nil
+}
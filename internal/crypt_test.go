@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// clearKeyEnv makes sure no key from the environment or config is used
+func clearKeyEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("HAB_KEY_FILE", "")
+	t.Setenv("HAB_PASSPHRASE", "")
+	t.Setenv("HAB_PASSPHRASE_COMMAND", "")
+	t.Setenv("HAB_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+}
+
+// newKeyFile generates an age key file in a temporary directory
+func newKeyFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.txt")
+	if err := GenerateKeyFile(path); err != nil {
+		t.Fatalf("GenerateKeyFile: %v", err)
+	}
+	return path
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	plaintext := []byte(`{"version": 4, "activities": {}}`)
+
+	for name, keys := range map[string]*Keys{
+		"passphrase": {Passphrase: "correct horse battery staple"},
+		"key file":   {KeyFile: newKeyFile(t)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ciphertext, err := Encrypt(plaintext, keys)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if !IsEncrypted(ciphertext) {
+				t.Fatalf("IsEncrypted = false for %q", ciphertext)
+			}
+			if bytes.Contains(ciphertext, []byte("activities")) {
+				t.Fatalf("ciphertext contains the plaintext")
+			}
+
+			decrypted, err := Decrypt(ciphertext, keys)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("Decrypt = %q, want %q", decrypted, plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptWrongPassphrase(t *testing.T) {
+	ciphertext, err := Encrypt([]byte("{}"), &Keys{Passphrase: "right"})
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := Decrypt(ciphertext, &Keys{Passphrase: "wrong"}); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Decrypt with wrong passphrase = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestDecryptWrongKeyFile(t *testing.T) {
+	ciphertext, err := Encrypt([]byte("{}"), &Keys{KeyFile: newKeyFile(t)})
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := Decrypt(ciphertext, &Keys{KeyFile: newKeyFile(t)}); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Decrypt with wrong key file = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestParseEncryptedWithWrongPassphrase(t *testing.T) {
+	clearKeyEnv(t)
+	ciphertext, err := Encrypt([]byte(`{"version": 4, "activities": {}}`), &Keys{Passphrase: "right"})
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	t.Setenv("HAB_PASSPHRASE", "wrong")
+	if _, err := ParseActivitiesData(ciphertext); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("ParseActivitiesData = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestNoKey(t *testing.T) {
+	clearKeyEnv(t)
+
+	if _, err := LoadKeys(); !errors.Is(err, ErrNoKey) {
+		t.Errorf("LoadKeys = %v, want ErrNoKey", err)
+	}
+
+	ciphertext, err := Encrypt([]byte("{}"), &Keys{Passphrase: "secret"})
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := ParseActivitiesData(ciphertext); !errors.Is(err, ErrNoKey) {
+		t.Errorf("ParseActivitiesData without a key = %v, want ErrNoKey", err)
+	}
+}
+
+func TestCommandKeyring(t *testing.T) {
+	passphrase, err := CommandKeyring{Command: "echo s3cret"}.Passphrase()
+	if err != nil || passphrase != "s3cret" {
+		t.Errorf("Passphrase = %q, %v, want s3cret", passphrase, err)
+	}
+
+	for _, command := range []string{"exit 3", "exit 0"} {
+		if _, err := (CommandKeyring{Command: command}).Passphrase(); err == nil {
+			t.Errorf("Passphrase from %q succeeded", command)
+		}
+	}
+}
+
+func TestPassphraseCommand(t *testing.T) {
+	clearKeyEnv(t)
+	t.Setenv("HAB_PASSPHRASE_COMMAND", "echo s3cret")
+
+	keys, err := LoadKeys()
+	if err != nil || keys.Passphrase != "s3cret" {
+		t.Fatalf("LoadKeys = %+v, %v, want the command's passphrase", keys, err)
+	}
+}
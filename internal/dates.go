@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormat is the layout entries are stored in
+const DateFormat = "2006-01-02"
+
+// maxRangeDays caps how many days a single range may cover
+const maxRangeDays = 366
+
+var (
+	// "3d", "2w", "3 days ago"; a leading minus is accepted too, though it
+	// must come after -- or in --date=-3d so it isn't read as a flag
+	relativeDatePattern = regexp.MustCompile(`^-?(\d+) ?(d|days?|w|weeks?)(?: ago)?$`)
+	isoWeekDatePattern  = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseWeekday parses a weekday name such as "mon" or "Monday"
+func ParseWeekday(input string) (time.Weekday, bool) {
+	weekday, ok := weekdayNames[strings.ToLower(strings.TrimSpace(input))]
+	return weekday, ok
+}
+
+// ParseDate resolves a date relative to now. It accepts YYYY-MM-DD, "today",
+// "yesterday", offsets such as "3d", "2w" or "3 days ago", weekday names ("mon" is the
+// most recent Monday, "last mon" the one before today) and ISO week dates
+// such as 2025-W03-2 (a bare week means its Monday). An empty input is today.
+func ParseDate(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch input {
+	case "", "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if date, err := time.ParseInLocation(DateFormat, input, now.Location()); err == nil {
+		return date, nil
+	}
+
+	if match := relativeDatePattern.FindStringSubmatch(input); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		if strings.HasPrefix(match[2], "w") {
+			n *= 7
+		}
+		return today.AddDate(0, 0, -n), nil
+	}
+
+	if match := isoWeekDatePattern.FindStringSubmatch(strings.ToUpper(input)); match != nil {
+		return parseISOWeekDate(match[1], match[2], match[3], now.Location())
+	}
+
+	if name, last := strings.CutPrefix(input, "last "); last {
+		if weekday, ok := ParseWeekday(name); ok {
+			days := (int(today.Weekday()) - int(weekday) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if weekday, ok := ParseWeekday(input); ok {
+		days := (int(today.Weekday()) - int(weekday) + 7) % 7
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date '%s', use YYYY-MM-DD, today, yesterday, 3d, mon, last mon or 2025-W03-2", input)
+}
+
+// parseISOWeekDate converts ISO 8601 year, week and optional weekday
+// (1 = Monday) strings to a date
+func parseISOWeekDate(yearStr, weekStr, dayStr string, loc *time.Location) (time.Time, error) {
+	year, _ := strconv.Atoi(yearStr)
+	week, _ := strconv.Atoi(weekStr)
+	day := 1
+	if dayStr != "" {
+		day, _ = strconv.Atoi(dayStr)
+	}
+
+	// Week 1 is the week containing January 4th
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	week1Monday := jan4.AddDate(0, 0, -offset)
+
+	date := week1Monday.AddDate(0, 0, (week-1)*7+day-1)
+	if isoYear, isoWeek := date.ISOWeek(); isoYear != year || isoWeek != week {
+		return time.Time{}, fmt.Errorf("invalid ISO week %d for %d", week, year)
+	}
+	return date, nil
+}
+
+// ParseDateRange resolves a single date or an inclusive "start..end" range,
+// where each side is anything ParseDate accepts, into YYYY-MM-DD strings
+func ParseDateRange(input string, now time.Time) ([]string, error) {
+	startStr, endStr, isRange := strings.Cut(input, "..")
+	if !isRange {
+		date, err := ParseDate(input, now)
+		if err != nil {
+			return nil, err
+		}
+		return []string{date.Format(DateFormat)}, nil
+	}
+
+	start, err := ParseDate(startStr, now)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ParseDate(endStr, now)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("range end %s is before start %s", end.Format(DateFormat), start.Format(DateFormat))
+	}
+
+	var dates []string
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if len(dates) == maxRangeDays {
+			return nil, fmt.Errorf("range is longer than %d days", maxRangeDays)
+		}
+		dates = append(dates, day.Format(DateFormat))
+	}
+	return dates, nil
+}
+
+// ParseWeekdayList parses a comma separated list of weekday names such as
+// "mon,wed,fri"
+func ParseWeekdayList(input string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, name := range strings.Split(input, ",") {
+		weekday, ok := ParseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday '%s', use names such as mon,wed,fri", strings.TrimSpace(name))
+		}
+		weekdays = append(weekdays, weekday)
+	}
+	return weekdays, nil
+}
+
+// FilterWeekdays keeps the YYYY-MM-DD dates falling on one of the weekdays
+func FilterWeekdays(dates []string, weekdays []time.Weekday) []string {
+	var filtered []string
+	for _, dateStr := range dates {
+		date, err := time.Parse(DateFormat, dateStr)
+		if err != nil {
+			continue
+		}
+		for _, weekday := range weekdays {
+			if date.Weekday() == weekday {
+				filtered = append(filtered, dateStr)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// ParsePeriod resolves a year (2025) or a month (2025-03) to its first and
+// last days
+func ParsePeriod(input string, loc *time.Location) (time.Time, time.Time, error) {
+	input = strings.TrimSpace(input)
+	if start, err := time.ParseInLocation("2006", input, loc); err == nil {
+		return start, start.AddDate(1, 0, -1), nil
+	}
+	if start, err := time.ParseInLocation("2006-01", input, loc); err == nil {
+		return start, start.AddDate(0, 1, -1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period '%s', use a year such as 2025 or a month such as 2025-03", input)
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testNow is a Wednesday afternoon
+var testNow = time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+
+func TestParseDate(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  string
+	}{
+		{"", "2025-03-12"},
+		{"today", "2025-03-12"},
+		{" Today ", "2025-03-12"},
+		{"yesterday", "2025-03-11"},
+		{"2025-01-15", "2025-01-15"},
+		{"3d", "2025-03-09"},
+		{"-3d", "2025-03-09"},
+		{"3 days ago", "2025-03-09"},
+		{"3 days", "2025-03-09"},
+		{"1 day ago", "2025-03-11"},
+		{"0d", "2025-03-12"},
+		{"2w", "2025-02-26"},
+		{"2 weeks ago", "2025-02-26"},
+		{"-1w", "2025-03-05"},
+		{"wed", "2025-03-12"},
+		{"mon", "2025-03-10"},
+		{"Friday", "2025-03-07"},
+		{"last wed", "2025-03-05"},
+		{"last mon", "2025-03-10"},
+		{"last thursday", "2025-03-06"},
+		{"2025-W01-1", "2024-12-30"},
+		{"2025-W01", "2024-12-30"},
+		{"2025W011", "2024-12-30"},
+		{"2025-w11-3", "2025-03-12"},
+		{"2020-W53-5", "2021-01-01"},
+		{"2026-W53-7", "2027-01-03"},
+	} {
+		got, err := ParseDate(test.input, testNow)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", test.input, err)
+			continue
+		}
+		if got.Format(DateFormat) != test.want {
+			t.Errorf("ParseDate(%q) = %s, want %s", test.input, got.Format(DateFormat), test.want)
+		}
+		if got.Hour() != 0 || got.Location() != time.UTC {
+			t.Errorf("ParseDate(%q) = %v, want midnight in now's location", test.input, got)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, input := range []string{"soon", "2025-13-01", "2025-02-30", "3x", "d", "last", "last someday", "2025-W53", "2025-W00", "2025-W01-8"} {
+		if date, err := ParseDate(input, testNow); err == nil {
+			t.Errorf("ParseDate(%q) = %s, want an error", input, date.Format(DateFormat))
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  []string
+	}{
+		{"yesterday", []string{"2025-03-11"}},
+		{"2025-02-27..2025-03-02", []string{"2025-02-27", "2025-02-28", "2025-03-01", "2025-03-02"}},
+		{"3d..today", []string{"2025-03-09", "2025-03-10", "2025-03-11", "2025-03-12"}},
+		{"today..today", []string{"2025-03-12"}},
+		{"2024-12-31..2025-W01-3", []string{"2024-12-31", "2025-01-01"}},
+	} {
+		got, err := ParseDateRange(test.input, testNow)
+		if err != nil {
+			t.Errorf("ParseDateRange(%q): %v", test.input, err)
+			continue
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("ParseDateRange(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseDateRangeCap(t *testing.T) {
+	// A leap year is exactly the longest range allowed
+	dates, err := ParseDateRange("2024-01-01..2024-12-31", testNow)
+	if err != nil || len(dates) != maxRangeDays {
+		t.Errorf("366-day range = %d dates, %v, want %d", len(dates), err, maxRangeDays)
+	}
+
+	if _, err := ParseDateRange("2023-01-01..2024-01-02", testNow); err == nil || !strings.Contains(err.Error(), "longer than") {
+		t.Errorf("367-day range error = %v, want it to be too long", err)
+	}
+}
+
+func TestParseDateRangeInvalid(t *testing.T) {
+	for _, input := range []string{"soon", "soon..today", "today..soon", "today..3d", "2025-01-01...2025-01-02"} {
+		if dates, err := ParseDateRange(input, testNow); err == nil {
+			t.Errorf("ParseDateRange(%q) = %v, want an error", input, dates)
+		}
+	}
+}
+
+func TestWeekdayFilters(t *testing.T) {
+	weekdays, err := ParseWeekdayList("mon, Wed,fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Weekday{time.Monday, time.Wednesday, time.Friday}; !slices.Equal(weekdays, want) {
+		t.Errorf("ParseWeekdayList = %v, want %v", weekdays, want)
+	}
+	if _, err := ParseWeekdayList("mon,someday"); err == nil {
+		t.Errorf("ParseWeekdayList with an unknown day succeeded")
+	}
+
+	dates, _ := ParseDateRange("2025-03-09..2025-03-15", testNow)
+	if got, want := FilterWeekdays(dates, weekdays), []string{"2025-03-10", "2025-03-12", "2025-03-14"}; !slices.Equal(got, want) {
+		t.Errorf("FilterWeekdays = %v, want %v", got, want)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	for _, test := range []struct {
+		input, start, end string
+	}{
+		{"2025", "2025-01-01", "2025-12-31"},
+		{"2024-02", "2024-02-01", "2024-02-29"},
+		{"2025-02", "2025-02-01", "2025-02-28"},
+	} {
+		start, end, err := ParsePeriod(test.input, time.UTC)
+		if err != nil || start.Format(DateFormat) != test.start || end.Format(DateFormat) != test.end {
+			t.Errorf("ParsePeriod(%q) = %s, %s, %v, want %s, %s", test.input,
+				start.Format(DateFormat), end.Format(DateFormat), err, test.start, test.end)
+		}
+	}
+	if _, _, err := ParsePeriod("2025-13", time.UTC); err == nil {
+		t.Errorf("ParsePeriod(2025-13) succeeded")
+	}
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultColor replaces colors hab doesn't know
+const DefaultColor = "green"
+
+// Layouts malformed dates are recognised in, so they can be normalised
+var looseDateLayouts = []string{
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"20060102",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Issue is a problem found in a habit, where it is and how it's repaired
+type Issue struct {
+	Key      string // habit key
+	Location string // field or entry, e.g. "dates[3]" or "color"
+	Problem  string
+	Repair   string
+}
+
+// String describes the issue for display
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s → %s", i.Location, i.Problem, i.Repair)
+}
+
+// ExcessEntries returns how many entries over its daily target an activity
+// has on each day that exceeds it. Slips of a habit to avoid are never
+// excess.
+func ExcessEntries(activity Activity) map[string]int {
+	if activity.IsAvoid() {
+		return nil
+	}
+	target := max(activity.TargetPerDay, 1)
+
+	excess := make(map[string]int)
+	for date, count := range NewDateCounts(activity.Dates) {
+		if count > target {
+			excess[date] = count - target
+		}
+	}
+	return excess
+}
+
+// RepairActivity checks a habit and returns a repaired copy with the
+// issues it fixed: malformed dates are normalised or dropped, future
+// entries and entries beyond the daily target are removed, and a missing
+// name, unknown color or polarity, target below 1, malformed creation date
+// and invalid reminder times are reset
+func RepairActivity(key string, activity Activity, today time.Time) (Activity, []Issue) {
+	var issues []Issue
+	report := func(location, problem, repair string) {
+		issues = append(issues, Issue{Key: key, Location: location, Problem: problem, Repair: repair})
+	}
+
+	if strings.TrimSpace(activity.Name) == "" {
+		activity.Name = strings.Title(strings.ReplaceAll(key, "_", " "))
+		report("name", "name is empty", fmt.Sprintf("set to '%s'", activity.Name))
+	}
+
+	if !IsValidColor(activity.Color) {
+		fixed := strings.ToLower(strings.TrimSpace(activity.Color))
+		if !IsValidColor(fixed) {
+			fixed = DefaultColor
+		}
+		report("color", fmt.Sprintf("unknown color '%s'", activity.Color), "set to "+fixed)
+		activity.Color = fixed
+	}
+
+	if activity.TargetPerDay < 1 {
+		report("target_per_day", fmt.Sprintf("target is %d", activity.TargetPerDay), "set to 1")
+		activity.TargetPerDay = 1
+	}
+
+	if activity.Polarity != PolarityBuild && activity.Polarity != PolarityAvoid {
+		report("polarity", fmt.Sprintf("unknown polarity '%s'", activity.Polarity), "cleared, a habit to build")
+		activity.Polarity = PolarityBuild
+	}
+
+	if _, err := time.Parse(DateFormat, activity.Created); activity.Created != "" && err != nil {
+		report("created", fmt.Sprintf("malformed date '%s'", activity.Created), "cleared")
+		activity.Created = ""
+	}
+
+	// Authors can't outnumber the entries they logged
+	activity.Authors = cloneAuthors(activity.Authors)
+	counts := NewDateCounts(activity.Dates)
+	for _, date := range sortedAuthorDates(activity.Authors) {
+		if authors := activity.Authors[date]; len(authors) > counts[date] {
+			report("authors["+date+"]", fmt.Sprintf("%d authors for %d entries", len(authors), counts[date]),
+				fmt.Sprintf("removed %d", len(authors)-counts[date]))
+			activity.Authors[date] = authors[:counts[date]]
+		}
+	}
+
+	// Normalise dates first so that duplicates among them are counted
+	todayStr := today.Format(DateFormat)
+	dates := make([]string, 0, len(activity.Dates))
+	for i, date := range activity.Dates {
+		location := fmt.Sprintf("dates[%d]", i)
+		if _, err := time.Parse(DateFormat, date); err != nil {
+			normalised, ok := normaliseDate(date)
+			if !ok {
+				report(location, fmt.Sprintf("malformed date '%s'", date), "removed")
+				continue
+			}
+			report(location, fmt.Sprintf("malformed date '%s'", date), "changed to "+normalised)
+			if authors, exists := activity.Authors[date]; exists {
+				delete(activity.Authors, date)
+				activity.Authors[normalised] = append(activity.Authors[normalised], authors...)
+			}
+			date = normalised
+		}
+		if date > todayStr {
+			report(location, fmt.Sprintf("future date %s", date), "removed")
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	// Keep the first entries of each day, up to the target
+	activity.Dates = dates
+	excess := ExcessEntries(activity)
+	if len(excess) > 0 {
+		excessDays := make([]string, 0, len(excess))
+		for date := range excess {
+			excessDays = append(excessDays, date)
+		}
+		sort.Strings(excessDays)
+		for _, date := range excessDays {
+			report(date, fmt.Sprintf("%d entries, target is %d", excess[date]+activity.TargetPerDay, activity.TargetPerDay),
+				fmt.Sprintf("removed %d", excess[date]))
+		}
+
+		kept := make(DateCounts)
+		activity.Dates = make([]string, 0, len(dates))
+		for _, date := range dates {
+			if kept[date] < activity.TargetPerDay {
+				kept[date]++
+				activity.Dates = append(activity.Dates, date)
+			}
+		}
+	}
+
+	// Drop the authors of entries removed above, which were reported already
+	activity.trimAllAuthors()
+
+	if len(activity.Reminders) > 0 {
+		seen := make(map[string]bool)
+		var reminders []string
+		for i, t := range activity.Reminders {
+			parsed, err := time.Parse("15:04", strings.TrimSpace(t))
+			if err != nil {
+				report(fmt.Sprintf("reminders[%d]", i), fmt.Sprintf("invalid time '%s'", t), "removed")
+				continue
+			}
+			if normalised := parsed.Format("15:04"); !seen[normalised] {
+				seen[normalised] = true
+				reminders = append(reminders, normalised)
+			}
+		}
+		sort.Strings(reminders)
+		activity.Reminders = reminders
+	}
+
+	return activity, issues
+}
+
+// sortedAuthorDates returns the dates that have authors in order
+func sortedAuthorDates(authors map[string][]string) []string {
+	dates := make([]string, 0, len(authors))
+	for date := range authors {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+// normaliseDate parses a date written in a common but non-standard way
+func normaliseDate(date string) (string, bool) {
+	date = strings.TrimSpace(date)
+	for _, layout := range looseDateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed.Format(DateFormat), true
+		}
+	}
+	return "", false
+}
+
+// Diagnose checks every habit and returns the issues found, ordered by
+// habit key
+func (hm *HabitManager) Diagnose(today time.Time) []Issue {
+	var issues []Issue
+	for _, key := range hm.SortedKeys() {
+		_, activityIssues := RepairActivity(key, hm.data.Activities[key], today)
+		issues = append(issues, activityIssues...)
+	}
+	return issues
+}
+
+// Repair fixes every issue Diagnose reports, saving once
+func (hm *HabitManager) Repair(today time.Time) ([]Issue, error) {
+	var issues []Issue
+	err := hm.Mutate(func(tx *Tx) error {
+		for _, key := range tx.data.sortedKeys() {
+			issues = append(issues, tx.RepairActivity(key, today)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// RepairActivity fixes the issues in one habit and returns them
+func (tx *Tx) RepairActivity(key string, today time.Time) []Issue {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return nil
+	}
+
+	repaired, issues := RepairActivity(key, activity, today)
+	if len(issues) > 0 {
+		tx.data.Activities[key] = repaired
+		tx.touched[key] = true
+	}
+	return issues
+}
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var doctorToday = time.Date(2025, 3, 12, 20, 0, 0, 0, time.UTC)
+
+// healthyActivity is a habit with nothing to repair
+func healthyActivity() Activity {
+	return Activity{
+		Name:         "Exercise",
+		Color:        "green",
+		Dates:        []string{"2025-03-01", "2025-03-02", "2025-03-02", "2025-03-12"},
+		TargetPerDay: 2,
+		Reminders:    []string{"07:30", "19:00"},
+		Authors:      map[string][]string{"2025-03-02": {"alice", "bob"}},
+		Created:      "2025-03-01",
+	}
+}
+
+func TestRepairActivityHealthy(t *testing.T) {
+	avoid := healthyActivity()
+	avoid.Polarity = PolarityAvoid
+	avoid.TargetPerDay = 1 // slips beyond the target are never excess
+
+	for name, activity := range map[string]Activity{"build": healthyActivity(), "avoid": avoid} {
+		repaired, issues := RepairActivity("exercise", activity, doctorToday)
+		if len(issues) != 0 {
+			t.Errorf("%s: issues = %v, want none", name, issues)
+		}
+		if !activityEqual(repaired, activity) {
+			t.Errorf("%s: repaired = %+v, want it unchanged", name, repaired)
+		}
+	}
+}
+
+func TestRepairActivity(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		key       string
+		change    func(*Activity)
+		want      func(*Activity)
+		locations []string
+	}{
+		{
+			name:      "empty name",
+			key:       "morning_run",
+			change:    func(a *Activity) { a.Name = " " },
+			want:      func(a *Activity) { a.Name = "Morning Run" },
+			locations: []string{"name"},
+		},
+		{
+			name:      "color in the wrong case",
+			change:    func(a *Activity) { a.Color = " Blue" },
+			want:      func(a *Activity) { a.Color = "blue" },
+			locations: []string{"color"},
+		},
+		{
+			name:      "unknown color",
+			change:    func(a *Activity) { a.Color = "plaid" },
+			want:      func(a *Activity) { a.Color = DefaultColor },
+			locations: []string{"color"},
+		},
+		{
+			name: "target below 1",
+			change: func(a *Activity) {
+				a.TargetPerDay = 0
+				a.Dates = []string{"2025-03-01"}
+				a.Authors = nil
+			},
+			want: func(a *Activity) {
+				a.TargetPerDay = 1
+				a.Dates = []string{"2025-03-01"}
+				a.Authors = nil
+			},
+			locations: []string{"target_per_day"},
+		},
+		{
+			name:      "unknown polarity",
+			change:    func(a *Activity) { a.Polarity = "sideways" },
+			want:      func(a *Activity) { a.Polarity = PolarityBuild },
+			locations: []string{"polarity"},
+		},
+		{
+			name:      "malformed creation date",
+			change:    func(a *Activity) { a.Created = "last week" },
+			want:      func(a *Activity) { a.Created = "" },
+			locations: []string{"created"},
+		},
+		{
+			name: "malformed dates",
+			change: func(a *Activity) {
+				a.Dates = []string{"2025/3/1", "2025-3-2", "20250303", "2025-03-04T08:00:00Z", "soon"}
+				a.Authors = map[string][]string{"2025-3-2": {"alice"}}
+			},
+			want: func(a *Activity) {
+				a.Dates = []string{"2025-03-01", "2025-03-02", "2025-03-03", "2025-03-04"}
+				a.Authors = map[string][]string{"2025-03-02": {"alice"}}
+			},
+			locations: []string{"dates[0]", "dates[1]", "dates[2]", "dates[3]", "dates[4]"},
+		},
+		{
+			name: "future dates",
+			change: func(a *Activity) {
+				a.Dates = []string{"2025-03-12", "2025-03-13", "2026/1/1"}
+				a.Authors = map[string][]string{"2025-03-13": {"bob"}}
+			},
+			want: func(a *Activity) {
+				a.Dates = []string{"2025-03-12"}
+				a.Authors = nil
+			},
+			locations: []string{"dates[1]", "dates[2]", "dates[2]"},
+		},
+		{
+			name: "entries beyond the target",
+			change: func(a *Activity) {
+				a.Dates = []string{"2025-03-01", "2025-03-01", "2025-03-02", "2025-03-01", "2025-03-01"}
+				a.Authors = map[string][]string{"2025-03-01": {"alice", "bob", "carol"}}
+			},
+			want: func(a *Activity) {
+				a.Dates = []string{"2025-03-01", "2025-03-01", "2025-03-02"}
+				a.Authors = map[string][]string{"2025-03-01": {"alice", "bob"}}
+			},
+			locations: []string{"2025-03-01"},
+		},
+		{
+			name:      "more authors than entries",
+			change:    func(a *Activity) { a.Authors = map[string][]string{"2025-03-01": {"alice", "bob"}} },
+			want:      func(a *Activity) { a.Authors = map[string][]string{"2025-03-01": {"alice"}} },
+			locations: []string{"authors[2025-03-01]"},
+		},
+		{
+			name:      "invalid reminder times",
+			change:    func(a *Activity) { a.Reminders = []string{"19:00", "7:30", "noon", "25:00", "07:30"} },
+			want:      func(a *Activity) { a.Reminders = []string{"07:30", "19:00"} },
+			locations: []string{"reminders[2]", "reminders[3]"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			key := test.key
+			if key == "" {
+				key = "exercise"
+			}
+			activity, want := healthyActivity(), healthyActivity()
+			test.change(&activity)
+			test.want(&want)
+			input := activity
+			input.Dates = slices.Clone(activity.Dates)
+			input.Authors = cloneAuthors(activity.Authors)
+
+			repaired, issues := RepairActivity(key, activity, doctorToday)
+			if !activityEqual(repaired, want) {
+				t.Errorf("repaired = %+v, want %+v", repaired, want)
+			}
+			if !activityEqual(activity, input) {
+				t.Errorf("RepairActivity changed its input to %+v", activity)
+			}
+
+			var locations []string
+			for _, issue := range issues {
+				if issue.Key != key || issue.Problem == "" || issue.Repair == "" {
+					t.Errorf("incomplete issue %+v", issue)
+				}
+				locations = append(locations, issue.Location)
+			}
+			if !slices.Equal(locations, test.locations) {
+				t.Errorf("issues at %v, want %v: %v", locations, test.locations, issues)
+			}
+
+			// A repaired habit has nothing left to repair
+			if _, again := RepairActivity(key, repaired, doctorToday); len(again) != 0 {
+				t.Errorf("repairing again found %v", again)
+			}
+		})
+	}
+}
+
+func TestRepairSavesOnce(t *testing.T) {
+	hm := newTestManager(t)
+	err := hm.Mutate(func(tx *Tx) error {
+		activity := tx.data.Activities["exercise"]
+		activity.Color = "plaid"
+		tx.data.Activities["exercise"] = activity
+		return tx.AddEntry("exercise", "2999-01-01")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hm.CountOn("exercise", "2999-01-01") != 1 {
+		t.Fatal("future entry missing from the index")
+	}
+	backups, _ := ListBackups(hm.DataFile())
+
+	issues, err := hm.Repair(doctorToday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || !strings.Contains(issues[1].Problem, "future date") {
+		t.Errorf("Repair = %v, want the color and the future date", issues)
+	}
+	if remaining := hm.Diagnose(doctorToday); len(remaining) != 0 {
+		t.Errorf("Diagnose after Repair = %v", remaining)
+	}
+	if hm.CountOn("exercise", "2999-01-01") != 0 {
+		t.Errorf("index still has the removed entry")
+	}
+	if after, _ := ListBackups(hm.DataFile()); len(after) != len(backups)+1 {
+		t.Errorf("Repair made %d backups, want 1", len(after)-len(backups))
+	}
+}
@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Goal kinds
+const (
+	GoalTotal = "total" // log Target entries between Start and End
+	GoalDaily = "daily" // meet the daily target every day from Start to End
+)
+
+// Goal statuses
+const (
+	GoalUpcoming = "upcoming"
+	GoalActive   = "active"
+	GoalPassed   = "passed"
+	GoalFailed   = "failed"
+)
+
+// Goal is a target set for a habit over a period, such as 100 entries in a
+// year or a 30-day challenge
+type Goal struct {
+	Habit  string `json:"habit"`
+	Kind   string `json:"kind"`
+	Target int    `json:"target"` // entries for total goals, days for daily ones
+	Start  string `json:"start"`
+	End    string `json:"end"`
+}
+
+// GoalProgress is how a goal stands on a given day
+type GoalProgress struct {
+	Goal         Goal
+	Status       string
+	Done         int       // entries logged, or days completed for daily goals
+	Days         int       // days from start to end
+	DaysLeft     int       // days from today to end, today included
+	CurrentPace  float64   // entries or days per day so far
+	RequiredPace float64   // entries or days per day needed from today on
+	Projected    time.Time // when the target was or will be reached at the current pace; zero if never
+}
+
+// Percent returns the share of the target done, at most 1
+func (p GoalProgress) Percent() float64 {
+	if p.Goal.Target <= 0 {
+		return 0
+	}
+	return math.Min(1, float64(p.Done)/float64(p.Goal.Target))
+}
+
+// OnTrack reports whether the goal is passed or will be reached by its end
+// at the current pace
+func (p GoalProgress) OnTrack() bool {
+	switch p.Status {
+	case GoalPassed, GoalUpcoming:
+		return true
+	case GoalActive:
+		return !p.Projected.IsZero() && p.Projected.Format(DateFormat) <= p.Goal.End
+	default:
+		return false
+	}
+}
+
+// ValidateGoal checks a goal's kind, target and dates
+func ValidateGoal(goal Goal) error {
+	if goal.Kind != GoalTotal && goal.Kind != GoalDaily {
+		return fmt.Errorf("unknown goal kind '%s', use %s or %s", goal.Kind, GoalTotal, GoalDaily)
+	}
+	if goal.Target < 1 {
+		return fmt.Errorf("goal target must be at least 1")
+	}
+
+	start, err := time.Parse(DateFormat, goal.Start)
+	if err != nil {
+		return fmt.Errorf("invalid goal start '%s', use YYYY-MM-DD", goal.Start)
+	}
+	end, err := time.Parse(DateFormat, goal.End)
+	if err != nil {
+		return fmt.Errorf("invalid goal end '%s', use YYYY-MM-DD", goal.End)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("goal ends %s, before it starts on %s", goal.End, goal.Start)
+	}
+	if goal.Kind == GoalDaily && daysBetween(start, end)+1 != goal.Target {
+		return fmt.Errorf("a %d-day challenge from %s must end on %s", goal.Target, goal.Start,
+			start.AddDate(0, 0, goal.Target-1).Format(DateFormat))
+	}
+	return nil
+}
+
+// ComputeGoalProgress measures a goal against its habit's entries as of
+// today. A challenge on a habit to avoid counts clean days from its own
+// start, even if that is before the habit was tracked, and a slip fails it
+// at once.
+func ComputeGoalProgress(goal Goal, activity Activity, today time.Time) GoalProgress {
+	start, _ := time.Parse(DateFormat, goal.Start)
+	end, _ := time.Parse(DateFormat, goal.End)
+	day0, _ := time.Parse(DateFormat, today.Format(DateFormat))
+
+	progress := GoalProgress{Goal: goal, Days: daysBetween(start, end) + 1}
+	counts := NewDateCounts(activity.Dates)
+	tracked := activity.Track(today)
+	if tracked.Start == "" || goal.Start < tracked.Start {
+		tracked.Start = goal.Start
+	}
+
+	// Walk the goal's days so far, noting when the target was reached
+	elapsed := 0
+	missed := false
+	for day := start; !day.After(end) && !day.After(day0); day = day.AddDate(0, 0, 1) {
+		elapsed++
+		date := day.Format(DateFormat)
+		count := counts[date]
+		if goal.Kind == GoalDaily {
+			if tracked.MetOn(date, count) {
+				progress.Done++
+			} else if day.Before(day0) || activity.IsAvoid() {
+				missed = true
+			}
+		} else {
+			progress.Done += count
+		}
+		if progress.Done >= goal.Target && progress.Projected.IsZero() {
+			progress.Projected = day
+		}
+	}
+
+	if !day0.After(end) {
+		from := day0
+		if from.Before(start) {
+			from = start
+		}
+		progress.DaysLeft = daysBetween(from, end) + 1
+	}
+
+	remaining := max(0, goal.Target-progress.Done)
+	if progress.DaysLeft > 0 {
+		progress.RequiredPace = float64(remaining) / float64(progress.DaysLeft)
+	}
+	if elapsed > 0 {
+		progress.CurrentPace = float64(progress.Done) / float64(elapsed)
+	}
+
+	switch {
+	case progress.Done >= goal.Target:
+		progress.Status = GoalPassed
+	case missed || day0.After(end):
+		progress.Status = GoalFailed
+	case day0.Before(start):
+		progress.Status = GoalUpcoming
+	default:
+		progress.Status = GoalActive
+	}
+
+	// Project when the rest will be done: a challenge finishes on its last
+	// day if no day is missed, a total goal at the pace so far
+	if progress.Status == GoalActive {
+		switch {
+		case goal.Kind == GoalDaily:
+			progress.Projected = end
+		case progress.CurrentPace > 0:
+			progress.Projected = day0.AddDate(0, 0, int(math.Ceil(float64(remaining)/progress.CurrentPace)))
+		}
+	}
+	return progress
+}
+
+// daysBetween counts the days from a to b, both at midnight UTC
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// GoalIDs returns every goal's ID in alphabetical order
+func (hm *HabitManager) GoalIDs() []string {
+	ids := make([]string, 0, len(hm.data.Goals))
+	for id := range hm.data.Goals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// HabitGoalIDs returns the IDs of a habit's goals in alphabetical order
+func (hm *HabitManager) HabitGoalIDs(key string) []string {
+	var ids []string
+	for _, id := range hm.GoalIDs() {
+		if hm.data.Goals[id].Habit == key {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// GetGoal returns a goal by ID
+func (hm *HabitManager) GetGoal(id string) (Goal, bool) {
+	goal, exists := hm.data.Goals[id]
+	return goal, exists
+}
+
+// GoalProgress measures a goal as of today
+func (hm *HabitManager) GoalProgress(id string, today time.Time) (GoalProgress, error) {
+	goal, exists := hm.data.Goals[id]
+	if !exists {
+		return GoalProgress{}, fmt.Errorf("goal '%s' does not exist", id)
+	}
+	return ComputeGoalProgress(goal, hm.data.Activities[goal.Habit], today), nil
+}
+
+// AddGoal adds a goal and returns its ID, which is generated from the
+// habit key when id is empty
+func (hm *HabitManager) AddGoal(id string, goal Goal) (string, error) {
+	err := hm.Mutate(func(tx *Tx) error {
+		var err error
+		id, err = tx.AddGoal(id, goal)
+		return err
+	})
+	return id, err
+}
+
+// DeleteGoal removes a goal
+func (hm *HabitManager) DeleteGoal(id string) error {
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.DeleteGoal(id)
+	})
+}
+
+// AddGoal adds a goal for an existing habit and returns its ID
+func (tx *Tx) AddGoal(id string, goal Goal) (string, error) {
+	activity, exists := tx.data.Activities[goal.Habit]
+	if !exists {
+		return "", fmt.Errorf("activity '%s' does not exist", goal.Habit)
+	}
+	if err := ValidateGoal(goal); err != nil {
+		return "", err
+	}
+	if activity.IsAvoid() && goal.Kind != GoalDaily {
+		return "", fmt.Errorf("'%s' is a habit to avoid, so its goals must be challenges: days in a row without a slip", goal.Habit)
+	}
+
+	if id == "" {
+		for n := 1; ; n++ {
+			id = fmt.Sprintf("%s-%d", goal.Habit, n)
+			if _, exists := tx.data.Goals[id]; !exists {
+				break
+			}
+		}
+	} else if _, exists := tx.data.Goals[id]; exists {
+		return "", fmt.Errorf("goal '%s' already exists", id)
+	}
+
+	if tx.data.Goals == nil {
+		tx.data.Goals = make(map[string]Goal)
+	}
+	tx.data.Goals[id] = goal
+	return id, nil
+}
+
+// DeleteGoal removes a goal
+func (tx *Tx) DeleteGoal(id string) error {
+	if _, exists := tx.data.Goals[id]; !exists {
+		return fmt.Errorf("goal '%s' does not exist", id)
+	}
+	delete(tx.data.Goals, id)
+	return nil
+}
+
+// Describe summarises a goal, e.g. "100 entries from 2025-01-01 to
+// 2025-12-31" or "30-day challenge from 2025-03-01"
+func (g Goal) Describe() string {
+	if g.Kind == GoalDaily {
+		return fmt.Sprintf("%d-day challenge from %s", g.Target, g.Start)
+	}
+	return fmt.Sprintf("%d entries from %s to %s", g.Target, g.Start, g.End)
+}
+
+// StatusText describes a goal's status, saying whether an active goal is on
+// track
+func (p GoalProgress) StatusText() string {
+	switch {
+	case p.Status != GoalActive:
+		return p.Status
+	case p.Projected.IsZero():
+		return "not started"
+	case p.OnTrack():
+		return "on track"
+	default:
+		return "behind"
+	}
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeGoalProgress(t *testing.T) {
+	today := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	challenge := Goal{Habit: "h", Kind: GoalDaily, Target: 7, Start: "2025-03-07", End: "2025-03-13"}
+
+	for _, test := range []struct {
+		name     string
+		goal     Goal
+		activity Activity
+		done     int
+		status   string
+	}{
+		{
+			name:     "avoid challenge starting before the habit was created",
+			goal:     challenge,
+			activity: Activity{Polarity: PolarityAvoid, Created: "2025-03-10"},
+			done:     4,
+			status:   GoalActive,
+		},
+		{
+			name:     "avoid challenge on a habit with no creation date or entries",
+			goal:     challenge,
+			activity: Activity{Polarity: PolarityAvoid},
+			done:     4,
+			status:   GoalActive,
+		},
+		{
+			name:     "avoid challenge with a slip",
+			goal:     challenge,
+			activity: Activity{Polarity: PolarityAvoid, Created: "2025-03-01", Dates: []string{"2025-03-08"}},
+			done:     3,
+			status:   GoalFailed,
+		},
+		{
+			name:     "avoid challenge with a slip today",
+			goal:     challenge,
+			activity: Activity{Polarity: PolarityAvoid, Created: "2025-03-01", Dates: []string{"2025-03-10"}},
+			done:     3,
+			status:   GoalFailed,
+		},
+		{
+			name:     "build challenge not yet logged today",
+			goal:     challenge,
+			activity: Activity{Dates: []string{"2025-03-07", "2025-03-08", "2025-03-09"}},
+			done:     3,
+			status:   GoalActive,
+		},
+		{
+			name:     "build challenge with a missed day",
+			goal:     challenge,
+			activity: Activity{Dates: []string{"2025-03-07", "2025-03-09", "2025-03-10"}},
+			done:     3,
+			status:   GoalFailed,
+		},
+		{
+			name:     "build challenge short of the daily target",
+			goal:     challenge,
+			activity: Activity{TargetPerDay: 2, Dates: []string{"2025-03-07", "2025-03-07", "2025-03-08"}},
+			done:     1,
+			status:   GoalFailed,
+		},
+		{
+			name:     "total goal reached",
+			goal:     Goal{Habit: "h", Kind: GoalTotal, Target: 3, Start: "2025-03-01", End: "2025-03-31"},
+			activity: Activity{Dates: []string{"2025-03-02", "2025-03-05", "2025-03-05"}},
+			done:     3,
+			status:   GoalPassed,
+		},
+		{
+			name:     "upcoming goal",
+			goal:     Goal{Habit: "h", Kind: GoalTotal, Target: 3, Start: "2025-04-01", End: "2025-04-30"},
+			activity: Activity{Dates: []string{"2025-03-02"}},
+			done:     0,
+			status:   GoalUpcoming,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			progress := ComputeGoalProgress(test.goal, test.activity, today)
+			if progress.Done != test.done || progress.Status != test.status {
+				t.Errorf("progress = %d %s, want %d %s", progress.Done, progress.Status, test.done, test.status)
+			}
+		})
+	}
+}
@@ -1,41 +1,64 @@
 package internal
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"time"
 )
 
+// ValidColors lists the colors a habit can be displayed in
+var ValidColors = []string{"red", "blue", "green", "magenta", "cyan", "yellow"}
+
+// IsValidColor reports whether color is one of ValidColors
+func IsValidColor(color string) bool {
+	for _, validColor := range ValidColors {
+		if color == validColor {
+			return true
+		}
+	}
+	return false
+}
+
 // Activity represents a single activity with its metadata
 type Activity struct {
 	Name         string   `json:"name"`
 	Color        string   `json:"color"`
 	Dates        []string `json:"dates"`
 	TargetPerDay int      `json:"target_per_day,omitempty"` // Optional: defaults to 1
+	Reminders    []string `json:"reminders,omitempty"`      // Optional: daily reminder times (HH:MM)
+
+	// Optional: who logged entries, by date. A date's entries beyond its
+	// list of authors are unattributed.
+	Authors map[string][]string `json:"authors,omitempty"`
+
+	// Optional: "avoid" for habits where each entry is a slip and days
+	// without entries count as success
+	Polarity string `json:"polarity,omitempty"`
+	Created  string `json:"created,omitempty"` // date the habit was created
 }
 
 // ActivitiesData represents the root JSON structure
 type ActivitiesData struct {
+	Version    int                 `json:"version"` // schema version, see DataVersion
 	Activities map[string]Activity `json:"activities"`
+	Goals      map[string]Goal     `json:"goals,omitempty"` // by goal ID
 }
 
 // HabitManager handles all habit-related operations
 type HabitManager struct {
-	dataFile string
-	data     *ActivitiesData
+	store     Store
+	storeErr  error // why the configured store couldn't be opened
+	retention BackupRetention
+	data      *ActivitiesData
+	index     map[string]DateCounts // per-activity entry counts by date
 }
 
-// getDefaultDataPath returns the default data file path based on OS
-func getDefaultDataPath() string {
-	// Check for HAB_DATA_FILE environment variable first
-	if dataFile := os.Getenv("HAB_DATA_FILE"); dataFile != "" {
-		return dataFile
-	}
-
+// getHabDir returns the per-user hab directory based on OS, or "" when it
+// can't be determined
+func getHabDir() string {
 	var configDir string
 	
 	switch runtime.GOOS {
@@ -53,58 +76,85 @@ func getDefaultDataPath() string {
 		}
 	}
 
-	// If we can't determine a config directory, fall back to current directory
 	if configDir == "" || os.Getenv("HOME") == "" {
-		return "data/activities.json"
+		return ""
 	}
 
-	return filepath.Join(configDir, "hab", "data", "activities.json")
+	return filepath.Join(configDir, "hab")
 }
 
-// NewHabitManager creates a new habit manager with default data file
-func NewHabitManager() *HabitManager {
-	return &HabitManager{
-		dataFile: getDefaultDataPath(),
-		data:     &ActivitiesData{Activities: make(map[string]Activity)},
+// getDefaultDataPath returns the active profile's data file path
+func getDefaultDataPath() string {
+	// Check for HAB_DATA_FILE environment variable first
+	if dataFile := os.Getenv("HAB_DATA_FILE"); dataFile != "" {
+		return dataFile
 	}
+
+	// Without a config directory this is relative to the current directory
+	return filepath.Join(profileDir(ActiveProfile()), "data", "activities.json")
 }
 
-// Load reads the activities data from the JSON file
-func (hm *HabitManager) Load() error {
-	// Ensure data directory exists
-	if err := os.MkdirAll(filepath.Dir(hm.dataFile), 0755); err != nil {
-		return fmt.Errorf("failed to create data directory: %w", err)
+// NewHabitManager creates a new habit manager using the configured store
+func NewHabitManager() *HabitManager {
+	store, err := OpenStore()
+	hm := NewHabitManagerWithStore(store)
+	hm.storeErr = err
+	if config, err := LoadConfig(); err == nil {
+		hm.retention = config.BackupRetention()
 	}
+	return hm
+}
 
-	// Create file if it doesn't exist
-	if _, err := os.Stat(hm.dataFile); os.IsNotExist(err) {
-		return hm.Save() // Create empty file with proper structure
+// NewHabitManagerWithStore creates a new habit manager backed by store
+func NewHabitManagerWithStore(store Store) *HabitManager {
+	return &HabitManager{
+		store:     store,
+		retention: DefaultBackupRetention,
+		data:      &ActivitiesData{Activities: make(map[string]Activity)},
+		index:     make(map[string]DateCounts),
 	}
+}
 
-	data, err := ioutil.ReadFile(hm.dataFile)
-	if err != nil {
-		return fmt.Errorf("failed to read data file: %w", err)
+// Load reads the activities data from the store
+func (hm *HabitManager) Load() error {
+	if hm.storeErr != nil {
+		return hm.storeErr
 	}
 
-	if err := json.Unmarshal(data, hm.data); err != nil {
-		return fmt.Errorf("failed to parse data file: %w", err)
+	data, err := hm.store.Load()
+	if err != nil {
+		return err
 	}
 
+	hm.data = data
+	hm.reindex()
 	return nil
 }
 
-// Save writes the activities data to the JSON file
+// Save writes the activities data to the store
 func (hm *HabitManager) Save() error {
-	data, err := json.MarshalIndent(hm.data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
+	if hm.storeErr != nil {
+		return hm.storeErr
 	}
+	return hm.store.Save(hm.data)
+}
 
-	if err := ioutil.WriteFile(hm.dataFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write data file: %w", err)
+// Store returns the store the manager loads from and saves to
+func (hm *HabitManager) Store() Store {
+	return hm.store
+}
+
+// DataFile returns the path of the file the store keeps data in
+func (hm *HabitManager) DataFile() string {
+	if hm.store == nil {
+		return ""
 	}
+	return hm.store.Path()
+}
 
-	return nil
+// Data returns all habit data, for copying it to another store
+func (hm *HabitManager) Data() *ActivitiesData {
+	return hm.data
 }
 
 // GetActivities returns all activities
@@ -112,6 +162,21 @@ func (hm *HabitManager) GetActivities() map[string]Activity {
 	return hm.data.Activities
 }
 
+// SortedKeys returns all activity keys in alphabetical order
+func (hm *HabitManager) SortedKeys() []string {
+	return hm.data.sortedKeys()
+}
+
+// sortedKeys returns all activity keys in alphabetical order
+func (d *ActivitiesData) sortedKeys() []string {
+	keys := make([]string, 0, len(d.Activities))
+	for key := range d.Activities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // GetActivity returns a specific activity by key
 func (hm *HabitManager) GetActivity(key string) (Activity, bool) {
 	activity, exists := hm.data.Activities[key]
@@ -120,91 +185,88 @@ func (hm *HabitManager) GetActivity(key string) (Activity, bool) {
 
 // CreateActivity creates a new activity
 func (hm *HabitManager) CreateActivity(key, name, color string, targetPerDay int) error {
-	if _, exists := hm.data.Activities[key]; exists {
-		return fmt.Errorf("activity '%s' already exists", key)
-	}
-
-	if targetPerDay <= 0 {
-		targetPerDay = 1
-	}
-
-	hm.data.Activities[key] = Activity{
-		Name:         name,
-		Color:        color,
-		Dates:        []string{},
-		TargetPerDay: targetPerDay,
-	}
-
-	return hm.Save()
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.CreateActivity(key, name, color, targetPerDay)
+	})
 }
 
 // AddEntry adds a date entry to an activity
 func (hm *HabitManager) AddEntry(key, dateStr string) error {
-	activity, exists := hm.data.Activities[key]
-	if !exists {
-		return fmt.Errorf("activity '%s' does not exist", key)
-	}
-
-	// Validate date format
-	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
-		return fmt.Errorf("invalid date format '%s', use YYYY-MM-DD", dateStr)
-	}
-
-	// Add the date
-	activity.Dates = append(activity.Dates, dateStr)
-	hm.data.Activities[key] = activity
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.AddEntry(key, dateStr)
+	})
+}
 
-	return hm.Save()
+// AddEntries adds one entry per date to an activity, saving once. The
+// entries are attributed to author unless it's empty.
+func (hm *HabitManager) AddEntries(key string, dates []string, author string) error {
+	return hm.Mutate(func(tx *Tx) error {
+		for _, dateStr := range dates {
+			if err := tx.AddEntryBy(key, dateStr, author); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-// RemoveEntry removes a date entry from an activity
-func (hm *HabitManager) RemoveEntry(key, dateStr string) error {
-	activity, exists := hm.data.Activities[key]
-	if !exists {
-		return fmt.Errorf("activity '%s' does not exist", key)
+// RemoveEntries removes one entry per date from an activity, saving once;
+// only author's entries if author isn't empty. Dates without such an entry
+// are skipped; the dates removed are returned.
+func (hm *HabitManager) RemoveEntries(key string, dates []string, author string) ([]string, error) {
+	if _, exists := hm.data.Activities[key]; !exists {
+		return nil, fmt.Errorf("activity '%s' does not exist", key)
 	}
 
-	// Find and remove the date (only first occurrence)
-	for i, date := range activity.Dates {
-		if date == dateStr {
-			activity.Dates = append(activity.Dates[:i], activity.Dates[i+1:]...)
-			hm.data.Activities[key] = activity
-			return hm.Save()
+	var removed []string
+	err := hm.Mutate(func(tx *Tx) error {
+		for _, dateStr := range dates {
+			if tx.RemoveEntryBy(key, dateStr, author) == nil {
+				removed = append(removed, dateStr)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return removed, nil
+}
 
-	return fmt.Errorf("date '%s' not found in activity '%s'", dateStr, key)
+// RemoveEntry removes a date entry from an activity
+func (hm *HabitManager) RemoveEntry(key, dateStr string) error {
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.RemoveEntry(key, dateStr)
+	})
 }
 
 // DeleteActivity removes an activity entirely
 func (hm *HabitManager) DeleteActivity(key string) error {
-	if _, exists := hm.data.Activities[key]; !exists {
-		return fmt.Errorf("activity '%s' does not exist", key)
-	}
-
-	delete(hm.data.Activities, key)
-	return hm.Save()
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.DeleteActivity(key)
+	})
 }
 
 // UpdateActivity updates activity metadata
 func (hm *HabitManager) UpdateActivity(key string, name, color string, targetPerDay int) error {
-	activity, exists := hm.data.Activities[key]
-	if !exists {
-		return fmt.Errorf("activity '%s' does not exist", key)
-	}
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.UpdateActivity(key, name, color, targetPerDay)
+	})
+}
 
-	if name != "" {
-		activity.Name = name
-	}
-	if color != "" {
-		activity.Color = color
-	}
-	if targetPerDay > 0 {
-		activity.TargetPerDay = targetPerDay
-	}
+// SetReminders replaces the daily reminder times (HH:MM) of an activity
+func (hm *HabitManager) SetReminders(key string, times []string) error {
+	return hm.Mutate(func(tx *Tx) error {
+		return tx.SetReminders(key, times)
+	})
+}
 
-	hm.data.Activities[key] = activity
-	return hm.Save()
+// Replace swaps all habit data for data and saves it
+func (hm *HabitManager) Replace(data *ActivitiesData) error {
+	return hm.Mutate(func(tx *Tx) error {
+		tx.Replace(data)
+		return nil
+	})
 }
 
 // GetStats returns statistics for an activity
@@ -218,65 +280,35 @@ func (hm *HabitManager) GetStats(key string) (map[string]interface{}, error) {
 	stats["name"] = activity.Name
 	stats["total_entries"] = len(activity.Dates)
 	stats["target_per_day"] = activity.TargetPerDay
+	stats["polarity"] = activity.Polarity
 
 	// Calculate unique days (for multi-frequency habits)
-	uniqueDays := make(map[string]bool)
-	for _, date := range activity.Dates {
-		uniqueDays[date] = true
-	}
-	stats["unique_days"] = len(uniqueDays)
+	stats["unique_days"] = len(hm.index[key])
 
 	// Calculate current streak
-	stats["current_streak"] = hm.calculateStreak(activity)
-
-	return stats, nil
-}
-
-// calculateStreak calculates the current streak for an activity
-func (hm *HabitManager) calculateStreak(activity Activity) int {
-	if len(activity.Dates) == 0 {
-		return 0
+	stats["current_streak"] = hm.calculateStreak(key)
+
+	// Strength and rolling completion rates, which a single miss only dents
+	if trends := hm.Trends(key, time.Now()); trends.Len() > 0 {
+		last := trends.Len() - 1
+		stats["strength"] = trends.Strength[last]
+		stats["rate_7d"] = trends.Rolling7[last]
+		stats["rate_30d"] = trends.Rolling30[last]
 	}
 
-	// Get unique days and sort them
-	uniqueDays := make(map[string]bool)
-	for _, date := range activity.Dates {
-		uniqueDays[date] = true
+	// Days without slips, for habits to avoid
+	if activity.IsAvoid() {
+		stats["clean_days"], stats["tracked_days"] = activity.CleanDays(time.Now())
 	}
 
-	var sortedDates []string
-	for date := range uniqueDays {
-		sortedDates = append(sortedDates, date)
-	}
-
-	if len(sortedDates) == 0 {
-		return 0
-	}
-
-	// Sort dates in descending order
-	for i := 0; i < len(sortedDates)-1; i++ {
-		for j := i + 1; j < len(sortedDates); j++ {
-			if sortedDates[i] < sortedDates[j] {
-				sortedDates[i], sortedDates[j] = sortedDates[j], sortedDates[i]
-			}
-		}
-	}
+	return stats, nil
+}
 
-	// Calculate streak from the most recent date
-	streak := 0
-	today := time.Now().Format("2006-01-02")
-	currentDate := today
-
-	for _, date := range sortedDates {
-		if date == currentDate {
-			streak++
-			// Move to previous day
-			t, _ := time.Parse("2006-01-02", currentDate)
-			currentDate = t.AddDate(0, 0, -1).Format("2006-01-02")
-		} else {
-			break
-		}
+// calculateStreak calculates the current streak for an activity, counting
+// back from today. For a habit to avoid it's the days since the last slip.
+func (hm *HabitManager) calculateStreak(key string) int {
+	if activity := hm.data.Activities[key]; activity.IsAvoid() {
+		return hm.index[key].CleanStreakEndingOn(time.Now(), activity.TrackingStart())
 	}
-
-	return streak
-}
\ No newline at end of file
+	return hm.index[key].StreakEndingOn(time.Now())
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// benchmarkDays is how many days of history the benchmarks log, three
+// entries a day, so a few thousand entries in all
+const benchmarkDays = 1200
+
+// benchmarkManager loads a manager with an unbroken run of entries ending
+// today, the worst case for streaks
+func benchmarkManager(b *testing.B) *HabitManager {
+	b.Helper()
+	today := time.Now()
+	dates := make([]string, 0, benchmarkDays*3)
+	for i := benchmarkDays - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format(DateFormat)
+		dates = append(dates, date, date, date)
+	}
+
+	hm := NewHabitManagerWithStore(NewJSONStore(filepath.Join(b.TempDir(), "activities.json")))
+	err := hm.Replace(&ActivitiesData{
+		Version: DataVersion,
+		Activities: map[string]Activity{
+			"exercise": {Name: "Exercise", Color: "green", Dates: dates, TargetPerDay: 2},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return hm
+}
+
+func BenchmarkCalculateStreak(b *testing.B) {
+	hm := benchmarkManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if streak := hm.calculateStreak("exercise"); streak != benchmarkDays {
+			b.Fatalf("streak = %d, want %d", streak, benchmarkDays)
+		}
+	}
+}
+
+func BenchmarkGetStats(b *testing.B) {
+	hm := benchmarkManager(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := hm.GetStats("exercise"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import "time"
+
+// DateCounts maps YYYY-MM-DD dates to the number of entries logged that day
+type DateCounts map[string]int
+
+// NewDateCounts counts the entries logged on each date
+func NewDateCounts(dates []string) DateCounts {
+	counts := make(DateCounts, len(dates))
+	for _, date := range dates {
+		counts[date]++
+	}
+	return counts
+}
+
+// StreakEndingOn returns how many consecutive days up to and including day
+// have at least one entry
+func (dc DateCounts) StreakEndingOn(day time.Time) int {
+	streak := 0
+	for dc[day.Format(DateFormat)] > 0 {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// reindex rebuilds the date counts of the given activities, or of every
+// activity when no keys are given
+func (hm *HabitManager) reindex(keys ...string) {
+	if len(keys) == 0 {
+		hm.index = make(map[string]DateCounts, len(hm.data.Activities))
+		for key := range hm.data.Activities {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		if activity, exists := hm.data.Activities[key]; exists {
+			hm.index[key] = NewDateCounts(activity.Dates)
+		} else {
+			delete(hm.index, key)
+		}
+	}
+}
+
+// DateCounts returns the per-day entry counts of an activity. The map is
+// shared with the manager and must not be modified.
+func (hm *HabitManager) DateCounts(key string) DateCounts {
+	return hm.index[key]
+}
+
+// CountOn returns how many entries an activity has on a date
+func (hm *HabitManager) CountOn(key, date string) int {
+	return hm.index[key][date]
+}
@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// JSONStore keeps habit data in a single human-readable JSON file, which
+// may be encrypted. Saves keep the file in the form it was loaded in.
+type JSONStore struct {
+	path string
+
+	keys      *Keys // cached so a passphrase command runs once
+	encrypted bool
+	known     bool // whether encrypted reflects the file yet
+}
+
+// NewJSONStore creates a store for the JSON file at path
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+// Path returns the JSON file path
+func (s *JSONStore) Path() string {
+	return s.path
+}
+
+// Load reads the JSON file, creating it if it doesn't exist
+func (s *JSONStore) Load() (*ActivitiesData, error) {
+	// Ensure data directory exists
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Create file if it doesn't exist
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		data := &ActivitiesData{Activities: make(map[string]Activity)}
+		return data, s.Save(data) // Create empty file with proper structure
+	}
+
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	s.encrypted, s.known = IsEncrypted(contents), true
+	if s.encrypted {
+		keys, err := s.loadKeys()
+		if err != nil {
+			return nil, err
+		}
+		if contents, err = Decrypt(contents, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	// Upgrade older files one version at a time, backing up each step
+	if len(bytes.TrimSpace(contents)) > 0 {
+		version, err := dataVersion(contents)
+		for err == nil && version < DataVersion {
+			if err = CreateBackup(s.path, time.Now()); err != nil {
+				return nil, fmt.Errorf("failed to back up data before migrating: %w", err)
+			}
+			if contents, version, err = migrateStep(contents); err == nil {
+				err = s.write(contents)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ParseActivitiesData(contents)
+}
+
+// SetEncrypted chooses whether later saves encrypt the file
+func (s *JSONStore) SetEncrypted(encrypted bool) {
+	s.encrypted, s.known = encrypted, true
+}
+
+// loadKeys returns the configured keys, looking them up on first use
+func (s *JSONStore) loadKeys() (*Keys, error) {
+	if s.keys == nil {
+		keys, err := LoadKeys()
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+	}
+	return s.keys, nil
+}
+
+// ParseActivitiesData decodes activities JSON, decrypting it with the
+// configured keys if needed. Empty input, such as git's stand-in for a
+// missing merge base, decodes to no activities.
+func ParseActivitiesData(contents []byte) (*ActivitiesData, error) {
+	if IsEncrypted(contents) {
+		keys, err := LoadKeys()
+		if err != nil {
+			return nil, err
+		}
+		if contents, err = Decrypt(contents, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	data := &ActivitiesData{Version: DataVersion, Activities: make(map[string]Activity)}
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return data, nil
+	}
+
+	contents, err := migrateData(contents)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, data); err != nil {
+		return nil, fmt.Errorf("failed to parse data file: %w", err)
+	}
+	if data.Activities == nil {
+		data.Activities = make(map[string]Activity)
+	}
+	return data, nil
+}
+
+// EncodeActivitiesData formats data the way the JSON store writes it,
+// stamped with the current DataVersion
+func EncodeActivitiesData(data *ActivitiesData) ([]byte, error) {
+	versioned := *data
+	versioned.Version = DataVersion
+	contents, err := json.MarshalIndent(versioned, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	return contents, nil
+}
+
+// Save writes data to the JSON file
+func (s *JSONStore) Save(data *ActivitiesData) error {
+	contents, err := EncodeActivitiesData(data)
+	if err != nil {
+		return err
+	}
+	return s.write(contents)
+}
+
+// write stores plaintext JSON in the file, encrypting it if the file is
+func (s *JSONStore) write(contents []byte) error {
+	if !s.known {
+		s.encrypted, s.known = IsEncryptedFile(s.path), true
+	}
+	if s.encrypted {
+		keys, err := s.loadKeys()
+		if err != nil {
+			return err
+		}
+		if contents, err = Encrypt(contents, keys); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(s.path, contents, 0644); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+
+	return nil
+}
+
+// List returns all activity keys in alphabetical order
+func (s *JSONStore) List() ([]string, error) {
+	data, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(data.Activities))
+	for key := range data.Activities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// AddEntry adds one entry for an activity and rewrites the file
+func (s *JSONStore) AddEntry(key, date string) error {
+	return s.update(func(tx *Tx) error {
+		return tx.AddEntry(key, date)
+	})
+}
+
+// RemoveEntry removes one entry for an activity and rewrites the file
+func (s *JSONStore) RemoveEntry(key, date string) error {
+	return s.update(func(tx *Tx) error {
+		return tx.RemoveEntry(key, date)
+	})
+}
+
+// QueryRange returns an activity's entries between start and end inclusive
+func (s *JSONStore) QueryRange(key, start, end string) ([]string, error) {
+	data, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	activity, exists := data.Activities[key]
+	if !exists {
+		return nil, fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	var dates []string
+	for _, date := range activity.Dates {
+		if date >= start && date <= end {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// update applies fn to the file's data and writes it back
+func (s *JSONStore) update(fn func(tx *Tx) error) error {
+	data, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	tx := &Tx{data: data, touched: make(map[string]bool)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return s.Save(data)
+}
@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// MergeConflict is a difference between two sides of a merge. Resolved
+// conflicts were settled by a rule; the rest need a person to look at them.
+type MergeConflict struct {
+	Key      string
+	Message  string
+	Resolved bool
+}
+
+// String describes the conflict for display
+func (c MergeConflict) String() string {
+	return fmt.Sprintf("%s: %s", c.Key, c.Message)
+}
+
+// Merge combines two edited copies of the data with their common ancestor.
+// Habits added or deleted on one side are added or deleted, and entries and
+// their authors are merged day by day (see mergeCounts). A field changed on
+// only one side takes that side's value. When both sides changed the same
+// field, ours wins for the name, color and polarity, the higher target
+// wins and reminders are combined, and each is reported as a resolved
+// conflict. A habit deleted on one side but changed on the other is kept
+// and reported as unresolved. Goals are merged by ID the same way.
+func Merge(base, ours, theirs *ActivitiesData) (*ActivitiesData, []MergeConflict) {
+	merged := &ActivitiesData{Activities: make(map[string]Activity)}
+	var conflicts []MergeConflict
+
+	keys := make(map[string]bool)
+	for _, data := range []*ActivitiesData{base, ours, theirs} {
+		for key := range data.Activities {
+			keys[key] = true
+		}
+	}
+
+	for _, key := range sortedSet(keys) {
+		baseActivity, inBase := base.Activities[key]
+		ourActivity, inOurs := ours.Activities[key]
+		theirActivity, inTheirs := theirs.Activities[key]
+
+		switch {
+		case inOurs && inTheirs:
+			if !inBase {
+				baseActivity = Activity{}
+			}
+			activity, activityConflicts := mergeActivity(key, baseActivity, ourActivity, theirActivity, inBase)
+			merged.Activities[key] = activity
+			conflicts = append(conflicts, activityConflicts...)
+		case inOurs && !inBase:
+			merged.Activities[key] = ourActivity
+		case inTheirs && !inBase:
+			merged.Activities[key] = theirActivity
+		case inOurs && !activityEqual(ourActivity, baseActivity):
+			merged.Activities[key] = ourActivity
+			conflicts = append(conflicts, MergeConflict{Key: key, Message: "deleted in theirs but changed in ours, kept ours"})
+		case inTheirs && !activityEqual(theirActivity, baseActivity):
+			merged.Activities[key] = theirActivity
+			conflicts = append(conflicts, MergeConflict{Key: key, Message: "deleted in ours but changed in theirs, kept theirs"})
+		}
+		// Anything else was deleted on one side and untouched on the other,
+		// or deleted on both
+	}
+
+	var goalConflicts []MergeConflict
+	merged.Goals, goalConflicts = mergeGoals(base.Goals, ours.Goals, theirs.Goals)
+	conflicts = append(conflicts, goalConflicts...)
+
+	return merged.clone(), conflicts
+}
+
+// mergeGoals merges goals by ID with the same rules as habits: added and
+// deleted goals are added and deleted, a goal changed on only one side
+// takes that side's version, and a goal changed on both sides keeps ours
+func mergeGoals(base, ours, theirs map[string]Goal) (map[string]Goal, []MergeConflict) {
+	merged := make(map[string]Goal)
+	var conflicts []MergeConflict
+
+	ids := make(map[string]bool)
+	for _, goals := range []map[string]Goal{base, ours, theirs} {
+		for id := range goals {
+			ids[id] = true
+		}
+	}
+
+	for _, id := range sortedSet(ids) {
+		baseGoal, inBase := base[id]
+		ourGoal, inOurs := ours[id]
+		theirGoal, inTheirs := theirs[id]
+		conflictKey := "goal " + id
+
+		switch {
+		case inOurs && inTheirs:
+			merged[id] = mergeField(baseGoal, ourGoal, theirGoal)
+			if ourGoal != theirGoal && ourGoal != baseGoal && theirGoal != baseGoal {
+				conflicts = append(conflicts, MergeConflict{Key: conflictKey, Resolved: true, Message: "changed on both sides, kept ours"})
+			}
+		case inOurs && (!inBase || ourGoal != baseGoal):
+			merged[id] = ourGoal
+			if inBase {
+				conflicts = append(conflicts, MergeConflict{Key: conflictKey, Message: "deleted in theirs but changed in ours, kept ours"})
+			}
+		case inTheirs && (!inBase || theirGoal != baseGoal):
+			merged[id] = theirGoal
+			if inBase {
+				conflicts = append(conflicts, MergeConflict{Key: conflictKey, Message: "deleted in ours but changed in theirs, kept theirs"})
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil, conflicts
+	}
+	return merged, conflicts
+}
+
+// mergeActivity merges one habit present on both sides, which may have
+// been added on both when it isn't in the base
+func mergeActivity(key string, base, ours, theirs Activity, inBase bool) (Activity, []MergeConflict) {
+	var conflicts []MergeConflict
+	merged := ours
+
+	merged.Name = mergeField(base.Name, ours.Name, theirs.Name)
+	if ours.Name != theirs.Name && ours.Name != base.Name && theirs.Name != base.Name {
+		conflicts = append(conflicts, MergeConflict{Key: key, Resolved: true,
+			Message: fmt.Sprintf("name changed to '%s' and '%s', kept '%s'", ours.Name, theirs.Name, ours.Name)})
+	}
+
+	merged.Color = mergeField(base.Color, ours.Color, theirs.Color)
+	if ours.Color != theirs.Color && ours.Color != base.Color && theirs.Color != base.Color {
+		conflicts = append(conflicts, MergeConflict{Key: key, Resolved: true,
+			Message: fmt.Sprintf("color changed to %s and %s, kept %s", ours.Color, theirs.Color, ours.Color)})
+	}
+
+	merged.TargetPerDay = mergeField(base.TargetPerDay, ours.TargetPerDay, theirs.TargetPerDay)
+	if ours.TargetPerDay != theirs.TargetPerDay && ours.TargetPerDay != base.TargetPerDay && theirs.TargetPerDay != base.TargetPerDay {
+		merged.TargetPerDay = max(ours.TargetPerDay, theirs.TargetPerDay)
+		conflicts = append(conflicts, MergeConflict{Key: key, Resolved: true,
+			Message: fmt.Sprintf("target changed to %d and %d, kept %d", ours.TargetPerDay, theirs.TargetPerDay, merged.TargetPerDay)})
+	}
+
+	// A habit added on both sides has no polarity to change from, and an
+	// empty polarity means a habit to build rather than an unset one
+	merged.Polarity = mergeField(base.Polarity, ours.Polarity, theirs.Polarity)
+	if !inBase {
+		merged.Polarity = ours.Polarity
+	}
+	if ours.Polarity != theirs.Polarity && (!inBase || ours.Polarity != base.Polarity && theirs.Polarity != base.Polarity) {
+		conflicts = append(conflicts, MergeConflict{Key: key, Resolved: true,
+			Message: fmt.Sprintf("polarity changed to %s and %s, kept %s",
+				polarityName(ours.Polarity), polarityName(theirs.Polarity), polarityName(ours.Polarity))})
+	}
+	merged.Created = mergeField(base.Created, ours.Created, theirs.Created)
+
+	switch {
+	case slices.Equal(ours.Reminders, theirs.Reminders), slices.Equal(theirs.Reminders, base.Reminders):
+		merged.Reminders = ours.Reminders
+	case slices.Equal(ours.Reminders, base.Reminders):
+		merged.Reminders = theirs.Reminders
+	default:
+		merged.Reminders = unionReminders(ours.Reminders, theirs.Reminders)
+		conflicts = append(conflicts, MergeConflict{Key: key, Resolved: true, Message: "reminders changed on both sides, kept all times"})
+	}
+
+	merged.Dates = mergeDates(base.Dates, ours.Dates, theirs.Dates)
+	merged.Authors = mergeAuthors(base, ours, theirs, merged.Dates)
+	return merged, conflicts
+}
+
+// polarityName names a polarity in merge messages
+func polarityName(polarity string) string {
+	if polarity == PolarityBuild {
+		return "build"
+	}
+	return polarity
+}
+
+// mergeField returns the side that changed a field, preferring ours when
+// both did
+func mergeField[T comparable](base, ours, theirs T) T {
+	if ours == base {
+		return theirs
+	}
+	return ours
+}
+
+// mergeDates merges entries by counting them per day, keeping ours' order
+// and appending the entries only theirs has
+func mergeDates(base, ours, theirs []string) []string {
+	baseCounts, ourCounts, theirCounts := NewDateCounts(base), NewDateCounts(ours), NewDateCounts(theirs)
+
+	emitted := make(DateCounts)
+	var merged []string
+	for _, dates := range [][]string{ours, theirs} {
+		for _, date := range dates {
+			if emitted[date] < mergeCounts(baseCounts[date], ourCounts[date], theirCounts[date]) {
+				emitted[date]++
+				merged = append(merged, date)
+			}
+		}
+	}
+	return merged
+}
+
+// mergeAuthors merges who logged each day's entries. A day's authors are a
+// multiset just like its entries, so they merge the same way, and are then
+// trimmed to the merged number of entries.
+func mergeAuthors(base, ours, theirs Activity, mergedDates []string) map[string][]string {
+	dates := make(map[string]bool)
+	for _, activity := range []Activity{base, ours, theirs} {
+		for date := range activity.Authors {
+			dates[date] = true
+		}
+	}
+
+	merged := Activity{Dates: mergedDates, Authors: make(map[string][]string)}
+	for date := range dates {
+		merged.Authors[date] = mergeDates(base.Authors[date], ours.Authors[date], theirs.Authors[date])
+	}
+	merged.trimAllAuthors()
+	return merged.Authors
+}
+
+// mergeCounts merges one day's entry counts. When both sides added entries
+// they are taken to be the same check-ins logged twice, so the larger count
+// wins rather than the sum; likewise when both removed some. When one side
+// added and the other removed, both changes apply.
+func mergeCounts(base, ours, theirs int) int {
+	switch {
+	case ours >= base && theirs >= base:
+		return max(ours, theirs)
+	case ours <= base && theirs <= base:
+		return min(ours, theirs)
+	default:
+		return max(0, ours+theirs-base)
+	}
+}
+
+// activityEqual reports whether two versions of a habit are the same
+func activityEqual(a, b Activity) bool {
+	return a.Name == b.Name &&
+		a.Color == b.Color &&
+		a.TargetPerDay == b.TargetPerDay &&
+		a.Polarity == b.Polarity &&
+		a.Created == b.Created &&
+		slices.Equal(a.Dates, b.Dates) &&
+		slices.Equal(a.Reminders, b.Reminders) &&
+		maps.EqualFunc(a.Authors, b.Authors, slices.Equal)
+}
+
+// sortedSet returns the keys of a set in alphabetical order
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MergeUnion combines two copies of the data that have no common ancestor.
+// Every habit in either copy is kept, each day ends up with the larger of
+// its two entry counts, and metadata and goals set in ours win over theirs.
+func MergeUnion(ours, theirs *ActivitiesData) *ActivitiesData {
+	merged := ours.clone()
+
+	for key, theirActivity := range theirs.clone().Activities {
+		activity, exists := merged.Activities[key]
+		if !exists {
+			merged.Activities[key] = theirActivity
+			continue
+		}
+
+		activity.Dates = unionDates(activity.Dates, theirActivity.Dates)
+		activity.Reminders = unionReminders(activity.Reminders, theirActivity.Reminders)
+		for date, authors := range theirActivity.Authors {
+			if activity.Authors == nil {
+				activity.Authors = make(map[string][]string)
+			}
+			activity.Authors[date] = unionDates(activity.Authors[date], authors)
+		}
+		activity.trimAllAuthors()
+		if activity.Name == "" {
+			activity.Name = theirActivity.Name
+		}
+		if activity.Color == "" {
+			activity.Color = theirActivity.Color
+		}
+		if activity.TargetPerDay == 0 {
+			activity.TargetPerDay = theirActivity.TargetPerDay
+		}
+		if activity.Polarity == "" {
+			activity.Polarity = theirActivity.Polarity
+		}
+		if activity.Created == "" || (theirActivity.Created != "" && theirActivity.Created < activity.Created) {
+			activity.Created = theirActivity.Created
+		}
+		merged.Activities[key] = activity
+	}
+
+	for id, goal := range theirs.Goals {
+		if _, exists := merged.Goals[id]; !exists {
+			if merged.Goals == nil {
+				merged.Goals = make(map[string]Goal)
+			}
+			merged.Goals[id] = goal
+		}
+	}
+
+	return merged
+}
+
+// unionDates keeps all of ours and appends the entries theirs has beyond
+// ours on each day, so a day logged twice on one side and once on the
+// other counts twice rather than three times
+func unionDates(ours, theirs []string) []string {
+	ourCounts := NewDateCounts(ours)
+	theirCounts := make(DateCounts)
+
+	union := append([]string{}, ours...)
+	for _, date := range theirs {
+		theirCounts[date]++
+		if theirCounts[date] > ourCounts[date] {
+			union = append(union, date)
+		}
+	}
+	return union
+}
+
+// unionReminders returns the sorted distinct reminder times of both sides
+func unionReminders(ours, theirs []string) []string {
+	seen := make(map[string]bool)
+	var union []string
+	for _, t := range append(append([]string{}, ours...), theirs...) {
+		if !seen[t] {
+			seen[t] = true
+			union = append(union, t)
+		}
+	}
+	sort.Strings(union)
+	return union
+}
@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// wantConflict is a conflict a merge should report, matched by key,
+// resolution and part of its message
+type wantConflict struct {
+	key      string
+	resolved bool
+	message  string
+}
+
+// habits builds data holding the given activities
+func habits(activities map[string]Activity) *ActivitiesData {
+	return &ActivitiesData{Activities: activities}
+}
+
+func checkConflicts(t *testing.T, got []MergeConflict, want []wantConflict) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("conflicts = %v, want %d", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Key != w.key || got[i].Resolved != w.resolved || !strings.Contains(got[i].Message, w.message) {
+			t.Errorf("conflict %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestMergeCounts(t *testing.T) {
+	for _, test := range []struct {
+		name                     string
+		base, ours, theirs, want int
+	}{
+		{"unchanged", 1, 1, 1, 1},
+		{"added in ours", 1, 2, 1, 2},
+		{"removed in theirs", 2, 2, 1, 1},
+		{"added on both sides", 0, 1, 2, 2},
+		{"removed on both sides", 3, 1, 2, 1},
+		{"added in ours and removed in theirs", 2, 3, 1, 2},
+		{"removed in ours and added in theirs", 1, 0, 3, 2},
+	} {
+		if got := mergeCounts(test.base, test.ours, test.theirs); got != test.want {
+			t.Errorf("%s: mergeCounts(%d, %d, %d) = %d, want %d",
+				test.name, test.base, test.ours, test.theirs, got, test.want)
+		}
+	}
+}
+
+func TestMergeDates(t *testing.T) {
+	base := []string{"2025-01-01", "2025-01-02"}
+	ours := []string{"2025-01-01", "2025-01-02", "2025-01-03"}
+	theirs := []string{"2025-01-02", "2025-01-04", "2025-01-04"}
+
+	want := []string{"2025-01-02", "2025-01-03", "2025-01-04", "2025-01-04"}
+	if got := mergeDates(base, ours, theirs); !slices.Equal(got, want) {
+		t.Errorf("mergeDates = %v, want %v", got, want)
+	}
+}
+
+func TestMergeActivities(t *testing.T) {
+	exercise := Activity{Name: "Exercise", Color: "green", TargetPerDay: 1, Dates: []string{"2025-01-01"}}
+	with := func(change func(*Activity)) Activity {
+		activity := exercise
+		activity.Dates = slices.Clone(exercise.Dates)
+		change(&activity)
+		return activity
+	}
+
+	for _, test := range []struct {
+		name               string
+		base, ours, theirs map[string]Activity
+		want               map[string]Activity
+		conflicts          []wantConflict
+	}{
+		{
+			name:   "added in ours",
+			base:   map[string]Activity{},
+			ours:   map[string]Activity{"exercise": exercise},
+			theirs: map[string]Activity{},
+			want:   map[string]Activity{"exercise": exercise},
+		},
+		{
+			name:   "added in theirs",
+			base:   map[string]Activity{},
+			ours:   map[string]Activity{},
+			theirs: map[string]Activity{"exercise": exercise},
+			want:   map[string]Activity{"exercise": exercise},
+		},
+		{
+			name:   "deleted in theirs and unchanged in ours",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": exercise},
+			theirs: map[string]Activity{},
+			want:   map[string]Activity{},
+		},
+		{
+			name:   "deleted on both sides",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{},
+			theirs: map[string]Activity{},
+			want:   map[string]Activity{},
+		},
+		{
+			name:      "deleted in theirs but changed in ours",
+			base:      map[string]Activity{"exercise": exercise},
+			ours:      map[string]Activity{"exercise": with(func(a *Activity) { a.Color = "red" })},
+			theirs:    map[string]Activity{},
+			want:      map[string]Activity{"exercise": with(func(a *Activity) { a.Color = "red" })},
+			conflicts: []wantConflict{{"exercise", false, "deleted in theirs but changed in ours"}},
+		},
+		{
+			name:      "deleted in ours but changed in theirs",
+			base:      map[string]Activity{"exercise": exercise},
+			ours:      map[string]Activity{},
+			theirs:    map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-02") })},
+			want:      map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-02") })},
+			conflicts: []wantConflict{{"exercise", false, "deleted in ours but changed in theirs"}},
+		},
+		{
+			name:   "fields changed on one side each",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": with(func(a *Activity) { a.Name = "Workout"; a.TargetPerDay = 2 })},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) { a.Color = "blue"; a.Polarity = PolarityAvoid })},
+			want: map[string]Activity{"exercise": with(func(a *Activity) {
+				a.Name, a.TargetPerDay, a.Color, a.Polarity = "Workout", 2, "blue", PolarityAvoid
+			})},
+		},
+		{
+			name:   "name and color changed on both sides",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": with(func(a *Activity) { a.Name = "Workout"; a.Color = "red" })},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) { a.Name = "Training"; a.Color = "blue" })},
+			want:   map[string]Activity{"exercise": with(func(a *Activity) { a.Name = "Workout"; a.Color = "red" })},
+			conflicts: []wantConflict{
+				{"exercise", true, "name changed to 'Workout' and 'Training', kept 'Workout'"},
+				{"exercise", true, "color changed to red and blue, kept red"},
+			},
+		},
+		{
+			name:      "target changed on both sides",
+			base:      map[string]Activity{"exercise": exercise},
+			ours:      map[string]Activity{"exercise": with(func(a *Activity) { a.TargetPerDay = 2 })},
+			theirs:    map[string]Activity{"exercise": with(func(a *Activity) { a.TargetPerDay = 3 })},
+			want:      map[string]Activity{"exercise": with(func(a *Activity) { a.TargetPerDay = 3 })},
+			conflicts: []wantConflict{{"exercise", true, "target changed to 2 and 3, kept 3"}},
+		},
+		{
+			name:   "same change on both sides",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": with(func(a *Activity) { a.Polarity = PolarityAvoid })},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) { a.Polarity = PolarityAvoid })},
+			want:   map[string]Activity{"exercise": with(func(a *Activity) { a.Polarity = PolarityAvoid })},
+		},
+		{
+			name:      "added on both sides with different polarities",
+			base:      map[string]Activity{},
+			ours:      map[string]Activity{"exercise": exercise},
+			theirs:    map[string]Activity{"exercise": with(func(a *Activity) { a.Polarity = PolarityAvoid })},
+			want:      map[string]Activity{"exercise": exercise},
+			conflicts: []wantConflict{{"exercise", true, "polarity changed to build and avoid, kept build"}},
+		},
+		{
+			name:      "reminders changed on both sides",
+			base:      map[string]Activity{"exercise": with(func(a *Activity) { a.Reminders = []string{"08:00"} })},
+			ours:      map[string]Activity{"exercise": with(func(a *Activity) { a.Reminders = []string{"09:00"} })},
+			theirs:    map[string]Activity{"exercise": with(func(a *Activity) { a.Reminders = []string{"07:00", "20:00"} })},
+			want:      map[string]Activity{"exercise": with(func(a *Activity) { a.Reminders = []string{"07:00", "09:00", "20:00"} })},
+			conflicts: []wantConflict{{"exercise", true, "reminders changed on both sides, kept all times"}},
+		},
+		{
+			name:   "entries added on both sides",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-02") })},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-02", "2025-01-03") })},
+			want:   map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-02", "2025-01-03") })},
+		},
+		{
+			name:   "entry removed in ours and added in theirs",
+			base:   map[string]Activity{"exercise": exercise},
+			ours:   map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = nil })},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = append(a.Dates, "2025-01-03") })},
+			want:   map[string]Activity{"exercise": with(func(a *Activity) { a.Dates = []string{"2025-01-03"} })},
+		},
+		{
+			name: "authors merged and trimmed to the merged entries",
+			base: map[string]Activity{"exercise": exercise},
+			ours: map[string]Activity{"exercise": with(func(a *Activity) {
+				a.Dates = append(a.Dates, "2025-01-02")
+				a.Authors = map[string][]string{"2025-01-02": {"alice"}}
+			})},
+			theirs: map[string]Activity{"exercise": with(func(a *Activity) {
+				a.Dates = append(a.Dates, "2025-01-02", "2025-01-03")
+				a.Authors = map[string][]string{"2025-01-02": {"bob"}, "2025-01-03": {"bob"}}
+			})},
+			want: map[string]Activity{"exercise": with(func(a *Activity) {
+				a.Dates = append(a.Dates, "2025-01-02", "2025-01-03")
+				a.Authors = map[string][]string{"2025-01-02": {"alice"}, "2025-01-03": {"bob"}}
+			})},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := Merge(habits(test.base), habits(test.ours), habits(test.theirs))
+			checkConflicts(t, conflicts, test.conflicts)
+
+			if len(merged.Activities) != len(test.want) {
+				t.Fatalf("merged habits = %v, want %v", merged.Activities, test.want)
+			}
+			for key, want := range test.want {
+				if got := merged.Activities[key]; !activityEqual(got, want) {
+					t.Errorf("merged %s = %+v, want %+v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeGoals(t *testing.T) {
+	goal := Goal{Habit: "exercise", Kind: GoalTotal, Target: 100, Start: "2025-01-01", End: "2025-12-31"}
+	raised := goal
+	raised.Target = 150
+	lowered := goal
+	lowered.Target = 50
+
+	for _, test := range []struct {
+		name               string
+		base, ours, theirs map[string]Goal
+		want               map[string]Goal
+		conflicts          []wantConflict
+	}{
+		{
+			name:   "added in theirs",
+			base:   nil,
+			ours:   nil,
+			theirs: map[string]Goal{"g": goal},
+			want:   map[string]Goal{"g": goal},
+		},
+		{
+			name:   "changed in theirs",
+			base:   map[string]Goal{"g": goal},
+			ours:   map[string]Goal{"g": goal},
+			theirs: map[string]Goal{"g": raised},
+			want:   map[string]Goal{"g": raised},
+		},
+		{
+			name:   "deleted in ours and unchanged in theirs",
+			base:   map[string]Goal{"g": goal},
+			ours:   nil,
+			theirs: map[string]Goal{"g": goal},
+			want:   nil,
+		},
+		{
+			name:      "deleted in ours but changed in theirs",
+			base:      map[string]Goal{"g": goal},
+			ours:      nil,
+			theirs:    map[string]Goal{"g": raised},
+			want:      map[string]Goal{"g": raised},
+			conflicts: []wantConflict{{"goal g", false, "deleted in ours but changed in theirs"}},
+		},
+		{
+			name:      "deleted in theirs but changed in ours",
+			base:      map[string]Goal{"g": goal},
+			ours:      map[string]Goal{"g": lowered},
+			theirs:    nil,
+			want:      map[string]Goal{"g": lowered},
+			conflicts: []wantConflict{{"goal g", false, "deleted in theirs but changed in ours"}},
+		},
+		{
+			name:      "changed on both sides",
+			base:      map[string]Goal{"g": goal},
+			ours:      map[string]Goal{"g": lowered},
+			theirs:    map[string]Goal{"g": raised},
+			want:      map[string]Goal{"g": lowered},
+			conflicts: []wantConflict{{"goal g", true, "changed on both sides, kept ours"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			base, ours, theirs := habits(nil), habits(nil), habits(nil)
+			base.Goals, ours.Goals, theirs.Goals = test.base, test.ours, test.theirs
+
+			merged, conflicts := Merge(base, ours, theirs)
+			checkConflicts(t, conflicts, test.conflicts)
+			if len(merged.Goals) != len(test.want) {
+				t.Fatalf("merged goals = %v, want %v", merged.Goals, test.want)
+			}
+			for id, want := range test.want {
+				if got := merged.Goals[id]; got != want {
+					t.Errorf("merged goal %s = %+v, want %+v", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeUnion(t *testing.T) {
+	ours := habits(map[string]Activity{
+		"exercise": {Name: "Exercise", Color: "green", Dates: []string{"2025-01-01", "2025-01-01"}, Created: "2025-01-01"},
+	})
+	theirs := habits(map[string]Activity{
+		"exercise": {Name: "Workout", Color: "blue", Dates: []string{"2025-01-01", "2025-01-02"}, Created: "2024-12-30"},
+		"reading":  {Name: "Reading", Color: "blue"},
+	})
+
+	merged := MergeUnion(ours, theirs)
+	want := Activity{Name: "Exercise", Color: "green", Dates: []string{"2025-01-01", "2025-01-01", "2025-01-02"}, Created: "2024-12-30"}
+	if got := merged.Activities["exercise"]; !activityEqual(got, want) {
+		t.Errorf("merged exercise = %+v, want %+v", got, want)
+	}
+	if _, exists := merged.Activities["reading"]; !exists {
+		t.Errorf("habit only in theirs was dropped")
+	}
+}
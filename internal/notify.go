@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Notifier delivers a reminder to the user
+type Notifier interface {
+	Notify(title, body string) error
+}
+
+// DBusNotifier sends desktop notifications through the freedesktop
+// org.freedesktop.Notifications service on the session bus
+type DBusNotifier struct{}
+
+// Notify shows a desktop notification
+func (DBusNotifier) Notify(title, body string) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	call := obj.Call("org.freedesktop.Notifications.Notify", 0,
+		"hab", uint32(0), "", title, body, []string{}, map[string]dbus.Variant{}, int32(-1))
+	if call.Err != nil {
+		return fmt.Errorf("failed to send notification: %w", call.Err)
+	}
+	return nil
+}
+
+// shellCommand prepares a command line to run through the platform's shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// CommandNotifier runs a shell command for each notification, passing the
+// text in the HAB_NOTIFY_TITLE and HAB_NOTIFY_BODY environment variables
+type CommandNotifier struct {
+	Command string
+}
+
+// Notify runs the configured command
+func (n CommandNotifier) Notify(title, body string) error {
+	cmd := shellCommand(n.Command)
+	cmd.Env = append(os.Environ(), "HAB_NOTIFY_TITLE="+title, "HAB_NOTIFY_BODY="+body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify command failed: %w", err)
+	}
+	return nil
+}
+
+// PrintNotifier writes notifications to standard output
+type PrintNotifier struct{}
+
+// Notify prints the notification
+func (PrintNotifier) Notify(title, body string) error {
+	fmt.Printf("🔔 %s: %s\n", title, body)
+	return nil
+}
+
+// FallbackNotifier tries each notifier in turn until one succeeds
+type FallbackNotifier []Notifier
+
+// Notify delivers through the first notifier that works
+func (f FallbackNotifier) Notify(title, body string) error {
+	var lastErr error
+	for _, n := range f {
+		if lastErr = n.Notify(title, body); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// NewNotifier picks the notifiers available on this system: desktop
+// notifications on Linux, then the command if one is configured, and
+// finally standard output
+func NewNotifier(command string) Notifier {
+	var chain FallbackNotifier
+	if runtime.GOOS == "linux" {
+		chain = append(chain, DBusNotifier{})
+	}
+	if command != "" {
+		chain = append(chain, CommandNotifier{Command: command})
+	}
+	return append(chain, PrintNotifier{})
+}
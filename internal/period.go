@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Summary periods
+const (
+	PeriodWeek  = "week" // Monday to Sunday, as ISO weeks
+	PeriodMonth = "month"
+)
+
+// Trends of a habit's completion rate from one period to the next
+const (
+	TrendUp     = "up"
+	TrendDown   = "down"
+	TrendSteady = "steady"
+	TrendNew    = "new" // not tracked in the previous period
+)
+
+// trendThreshold is the change in completion rate below which a habit is
+// considered steady
+const trendThreshold = 0.05
+
+// Period is a calendar week or month
+type Period struct {
+	Kind  string
+	Start time.Time // first day, at midnight UTC
+	End   time.Time // last day
+}
+
+// PeriodContaining returns the week or month that includes date
+func PeriodContaining(kind string, date time.Time) (Period, error) {
+	day, _ := time.Parse(DateFormat, date.Format(DateFormat))
+	switch kind {
+	case PeriodWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 0, 6)}, nil
+	case PeriodMonth:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 1, -1)}, nil
+	}
+	return Period{}, fmt.Errorf("unknown period '%s', use %s or %s", kind, PeriodWeek, PeriodMonth)
+}
+
+// Previous returns the period just before p
+func (p Period) Previous() Period {
+	previous, _ := PeriodContaining(p.Kind, p.Start.AddDate(0, 0, -1))
+	return previous
+}
+
+// Label names the period, e.g. "2025-W03" or "January 2025"
+func (p Period) Label() string {
+	if p.Kind == PeriodWeek {
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return p.Start.Format("January 2006")
+}
+
+// HabitPeriod is how a habit did over a period
+type HabitPeriod struct {
+	CheckIns int // entries logged, which are slips for habits to avoid
+	DaysMet  int // days the target was met, or clean days for habits to avoid
+	Days     int // days tracked: from the habit's start up to today
+}
+
+// Rate returns the share of tracked days that were met
+func (hp HabitPeriod) Rate() float64 {
+	if hp.Days == 0 {
+		return 0
+	}
+	return float64(hp.DaysMet) / float64(hp.Days)
+}
+
+// SummariseHabitPeriod measures a habit over a period, counting only days
+// from when it was first tracked up to today
+func SummariseHabitPeriod(activity Activity, period Period, today time.Time) HabitPeriod {
+	var hp HabitPeriod
+	tracked := activity.Track(today)
+	if tracked.Start == "" {
+		return hp
+	}
+
+	counts := NewDateCounts(activity.Dates)
+	for day := period.Start; !day.After(period.End); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DateFormat)
+		if date < tracked.Start || date > tracked.Today {
+			continue
+		}
+		hp.Days++
+		hp.CheckIns += counts[date]
+		if tracked.MetOn(date, counts[date]) {
+			hp.DaysMet++
+		}
+	}
+	return hp
+}
+
+// HabitSummary compares a habit's period with the one before
+type HabitSummary struct {
+	Key      string
+	Activity Activity
+	Current  HabitPeriod
+	Previous HabitPeriod
+}
+
+// Change returns how much the completion rate moved since the previous
+// period, from -1 to 1
+func (hs HabitSummary) Change() float64 {
+	return hs.Current.Rate() - hs.Previous.Rate()
+}
+
+// Trend says whether the completion rate went up, down or held steady
+func (hs HabitSummary) Trend() string {
+	// Round off float error so a change of exactly 5 points isn't steady
+	switch change := math.Round(hs.Change()*1e9) / 1e9; {
+	case hs.Previous.Days == 0:
+		return TrendNew
+	case change >= trendThreshold:
+		return TrendUp
+	case change <= -trendThreshold:
+		return TrendDown
+	default:
+		return TrendSteady
+	}
+}
+
+// PeriodSummary aggregates every habit over a period and the one before
+type PeriodSummary struct {
+	Period   Period
+	Previous Period
+	Habits   []HabitSummary // habits tracked in either period, by key
+
+	// Entries of habits to build; slips of habits to avoid are counted
+	// apart so they don't inflate the check-ins
+	CheckIns, PreviousCheckIns int
+	Slips, PreviousSlips       int
+}
+
+// Best returns the habit with the highest completion rate in the period,
+// or false when no habit was tracked in it
+func (s PeriodSummary) Best() (HabitSummary, bool) {
+	return s.ranked(func(a, b float64) bool { return a > b })
+}
+
+// Worst returns the habit with the lowest completion rate in the period,
+// or false when no habit was tracked in it
+func (s PeriodSummary) Worst() (HabitSummary, bool) {
+	return s.ranked(func(a, b float64) bool { return a < b })
+}
+
+// ranked returns the habit tracked in the period whose rate comes first
+// by better, the first by key on a tie
+func (s PeriodSummary) ranked(better func(a, b float64) bool) (HabitSummary, bool) {
+	var pick HabitSummary
+	found := false
+	for _, habit := range s.Habits {
+		if habit.Current.Days == 0 {
+			continue
+		}
+		if !found || better(habit.Current.Rate(), pick.Current.Rate()) {
+			pick, found = habit, true
+		}
+	}
+	return pick, found
+}
+
+// SummarisePeriod compares every habit over the week or month containing
+// date with the period before, counting days up to today
+func (hm *HabitManager) SummarisePeriod(kind string, date, today time.Time) (PeriodSummary, error) {
+	period, err := PeriodContaining(kind, date)
+	if err != nil {
+		return PeriodSummary{}, err
+	}
+	summary := PeriodSummary{Period: period, Previous: period.Previous()}
+
+	keys := make([]string, 0, len(hm.data.Activities))
+	for key := range hm.data.Activities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		activity := hm.data.Activities[key]
+		habit := HabitSummary{
+			Key:      key,
+			Activity: activity,
+			Current:  SummariseHabitPeriod(activity, summary.Period, today),
+			Previous: SummariseHabitPeriod(activity, summary.Previous, today),
+		}
+		if habit.Current.Days == 0 && habit.Previous.Days == 0 {
+			continue
+		}
+
+		if activity.IsAvoid() {
+			summary.Slips += habit.Current.CheckIns
+			summary.PreviousSlips += habit.Previous.CheckIns
+		} else {
+			summary.CheckIns += habit.Current.CheckIns
+			summary.PreviousCheckIns += habit.Previous.CheckIns
+		}
+		summary.Habits = append(summary.Habits, habit)
+	}
+	return summary, nil
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(DateFormat, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestPeriodContaining(t *testing.T) {
+	for _, test := range []struct {
+		kind, date, start, end, label, previous string
+	}{
+		{PeriodWeek, "2025-03-12", "2025-03-10", "2025-03-16", "2025-W11", "2025-W10"},
+		{PeriodWeek, "2025-03-16", "2025-03-10", "2025-03-16", "2025-W11", "2025-W10"},
+		{PeriodWeek, "2025-01-01", "2024-12-30", "2025-01-05", "2025-W01", "2024-W52"},
+		{PeriodWeek, "2021-01-03", "2020-12-28", "2021-01-03", "2020-W53", "2020-W52"},
+		{PeriodWeek, "2021-01-04", "2021-01-04", "2021-01-10", "2021-W01", "2020-W53"},
+		{PeriodMonth, "2025-01-31", "2025-01-01", "2025-01-31", "January 2025", "December 2024"},
+		{PeriodMonth, "2024-02-10", "2024-02-01", "2024-02-29", "February 2024", "January 2024"},
+		{PeriodMonth, "2025-02-10", "2025-02-01", "2025-02-28", "February 2025", "January 2025"},
+		{PeriodMonth, "2025-04-30", "2025-04-01", "2025-04-30", "April 2025", "March 2025"},
+	} {
+		period, err := PeriodContaining(test.kind, testDate(t, test.date).Add(15*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := period.Start.Format(DateFormat); got != test.start {
+			t.Errorf("%s containing %s starts %s, want %s", test.kind, test.date, got, test.start)
+		}
+		if got := period.End.Format(DateFormat); got != test.end {
+			t.Errorf("%s containing %s ends %s, want %s", test.kind, test.date, got, test.end)
+		}
+		if got := period.Label(); got != test.label {
+			t.Errorf("%s containing %s is labelled %s, want %s", test.kind, test.date, got, test.label)
+		}
+		previous := period.Previous()
+		if got := previous.Label(); got != test.previous {
+			t.Errorf("%s before %s is %s, want %s", test.kind, test.label, got, test.previous)
+		}
+		if !previous.End.AddDate(0, 0, 1).Equal(period.Start) {
+			t.Errorf("%s ends %s, not the day before %s starts", previous.Label(), previous.End.Format(DateFormat), test.label)
+		}
+	}
+
+	if _, err := PeriodContaining("fortnight", time.Now()); err == nil {
+		t.Errorf("PeriodContaining with an unknown kind succeeded")
+	}
+}
+
+func TestSummariseHabitPeriod(t *testing.T) {
+	week, _ := PeriodContaining(PeriodWeek, testDate(t, "2025-03-12"))
+	today := testDate(t, "2025-03-12").Add(18 * time.Hour)
+
+	for _, test := range []struct {
+		name     string
+		activity Activity
+		want     HabitPeriod
+	}{
+		{
+			name: "counts days from creation up to today",
+			activity: Activity{TargetPerDay: 2, Created: "2025-03-11",
+				Dates: []string{"2025-03-11", "2025-03-11", "2025-03-12"}},
+			want: HabitPeriod{CheckIns: 3, DaysMet: 1, Days: 2},
+		},
+		{
+			name: "habit to avoid counts clean days",
+			activity: Activity{Polarity: PolarityAvoid, Created: "2025-03-01",
+				Dates: []string{"2025-03-05", "2025-03-11"}},
+			want: HabitPeriod{CheckIns: 1, DaysMet: 2, Days: 3},
+		},
+		{
+			name:     "created after the period",
+			activity: Activity{Created: "2025-03-20"},
+			want:     HabitPeriod{},
+		},
+		{
+			name:     "never tracked",
+			activity: Activity{},
+			want:     HabitPeriod{},
+		},
+	} {
+		if got := SummariseHabitPeriod(test.activity, week, today); got != test.want {
+			t.Errorf("%s: %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTrend(t *testing.T) {
+	summary := func(current, previous, days int) HabitSummary {
+		return HabitSummary{
+			Current:  HabitPeriod{DaysMet: current, Days: days},
+			Previous: HabitPeriod{DaysMet: previous, Days: days},
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		hs   HabitSummary
+		want string
+	}{
+		{"not tracked before", HabitSummary{Current: HabitPeriod{DaysMet: 1, Days: 7}}, TrendNew},
+		{"no change", summary(5, 5, 7), TrendSteady},
+		{"up 4 points", summary(54, 50, 100), TrendSteady},
+		{"up 5 points", summary(11, 10, 20), TrendUp},
+		{"down 4 points", summary(46, 50, 100), TrendSteady},
+		{"down 5 points", summary(9, 10, 20), TrendDown},
+		{"down 5 points at another rate", summary(2, 3, 20), TrendDown},
+		{"up a day of a week", summary(6, 5, 7), TrendUp},
+	} {
+		if got := test.hs.Trend(); got != test.want {
+			t.Errorf("%s: Trend = %s (change %v), want %s", test.name, got, test.hs.Change(), test.want)
+		}
+	}
+}
+
+func TestBestAndWorst(t *testing.T) {
+	rated := func(key string, met, days int) HabitSummary {
+		return HabitSummary{Key: key, Current: HabitPeriod{DaysMet: met, Days: days}}
+	}
+	summary := PeriodSummary{Habits: []HabitSummary{
+		rated("a", 7, 7),
+		rated("b", 7, 7),
+		rated("c", 0, 0), // not tracked this period
+		rated("d", 1, 7),
+		rated("e", 1, 7),
+	}}
+
+	if best, ok := summary.Best(); !ok || best.Key != "a" {
+		t.Errorf("Best = %s, %t, want a", best.Key, ok)
+	}
+	if worst, ok := summary.Worst(); !ok || worst.Key != "d" {
+		t.Errorf("Worst = %s, %t, want d", worst.Key, ok)
+	}
+
+	empty := PeriodSummary{Habits: []HabitSummary{rated("c", 0, 0)}}
+	if _, ok := empty.Best(); ok {
+		t.Errorf("Best of untracked habits succeeded")
+	}
+}
+
+func TestSummarisePeriod(t *testing.T) {
+	hm := NewHabitManagerWithStore(NewJSONStore(filepath.Join(t.TempDir(), "activities.json")))
+	err := hm.Replace(&ActivitiesData{Activities: map[string]Activity{
+		"exercise": {Name: "Exercise", Created: "2025-03-01", Dates: []string{"2025-03-04", "2025-03-10", "2025-03-11"}},
+		"nosugar":  {Name: "No sugar", Polarity: PolarityAvoid, Created: "2025-03-01", Dates: []string{"2025-03-03", "2025-03-11"}},
+		"later":    {Name: "Later", Created: "2025-04-01"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := testDate(t, "2025-03-12").Add(18 * time.Hour)
+	summary, err := hm.SummarisePeriod(PeriodWeek, today, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Habits) != 2 || summary.Habits[0].Key != "exercise" || summary.Habits[1].Key != "nosugar" {
+		t.Fatalf("habits = %+v, want exercise and nosugar", summary.Habits)
+	}
+	if summary.CheckIns != 2 || summary.PreviousCheckIns != 1 {
+		t.Errorf("check-ins = %d and %d before, want 2 and 1", summary.CheckIns, summary.PreviousCheckIns)
+	}
+	if summary.Slips != 1 || summary.PreviousSlips != 1 {
+		t.Errorf("slips = %d and %d before, want 1 and 1", summary.Slips, summary.PreviousSlips)
+	}
+	if got := summary.Habits[1].Current; got != (HabitPeriod{CheckIns: 1, DaysMet: 2, Days: 3}) {
+		t.Errorf("nosugar this week = %+v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Habit polarities. A habit to build succeeds on days with entries; a habit
+// to avoid succeeds on days without any, and each entry records a slip.
+const (
+	PolarityBuild = ""
+	PolarityAvoid = "avoid"
+)
+
+// IsAvoid reports whether the habit is one to avoid
+func (a Activity) IsAvoid() bool {
+	return a.Polarity == PolarityAvoid
+}
+
+// TrackingStart returns the first day a habit is tracked: the day it was
+// created, or its earliest entry if that is sooner or the creation date is
+// unknown. It is empty for a habit with neither.
+func (a Activity) TrackingStart() string {
+	start := a.Created
+	for _, date := range a.Dates {
+		if start == "" || date < start {
+			start = date
+		}
+	}
+	return start
+}
+
+// TrackedDays judges a habit's days as of today. It works out the tracking
+// start once, so judging every day of a grid or period doesn't rescan the
+// entries for each one.
+type TrackedDays struct {
+	Activity Activity
+	Start    string // see TrackingStart
+	Today    string
+}
+
+// Track prepares to judge an activity's days as of today
+func (a Activity) Track(today time.Time) TrackedDays {
+	return TrackedDays{Activity: a, Start: a.TrackingStart(), Today: today.Format(DateFormat)}
+}
+
+// IsCleanDay reports whether a day counts as a success for a habit to
+// avoid: it is tracked, not after today, and has no slips
+func (t TrackedDays) IsCleanDay(date string, count int) bool {
+	return t.Activity.IsAvoid() && count == 0 && t.Start != "" && date >= t.Start && date <= t.Today
+}
+
+// MetOn reports whether a habit succeeded on a day with count entries: the
+// daily target was met or, for a habit to avoid, the day was clean
+func (t TrackedDays) MetOn(date string, count int) bool {
+	if t.Activity.IsAvoid() {
+		return t.IsCleanDay(date, count)
+	}
+	return count >= max(1, t.Activity.TargetPerDay)
+}
+
+// CleanStreakEndingOn returns how many consecutive days up to and including
+// day have no entries, stopping at start
+func (dc DateCounts) CleanStreakEndingOn(day time.Time, start string) int {
+	if start == "" {
+		return 0
+	}
+	streak := 0
+	for date := day.Format(DateFormat); date >= start && dc[date] == 0; date = day.Format(DateFormat) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// CleanDays counts the tracked days of a habit to avoid up to today and
+// how many of them had no slips
+func (a Activity) CleanDays(today time.Time) (clean, tracked int) {
+	start, err := time.Parse(DateFormat, a.TrackingStart())
+	if err != nil {
+		return 0, 0
+	}
+	end, _ := time.Parse(DateFormat, today.Format(DateFormat))
+	if end.Before(start) {
+		return 0, 0
+	}
+
+	tracked = daysBetween(start, end) + 1
+	clean = tracked
+	for date := range NewDateCounts(a.Dates) {
+		if date >= start.Format(DateFormat) && date <= end.Format(DateFormat) {
+			clean--
+		}
+	}
+	return clean, tracked
+}
+
+// SetPolarity makes an activity a habit to build or to avoid
+func (tx *Tx) SetPolarity(key, polarity string) error {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+	if polarity != PolarityBuild && polarity != PolarityAvoid {
+		return fmt.Errorf("unknown polarity '%s', use '%s' or leave it empty", polarity, PolarityAvoid)
+	}
+
+	activity.Polarity = polarity
+	tx.data.Activities[key] = activity
+	tx.touched[key] = true
+	return nil
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+var polarityToday = time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+
+// noSugar is a habit to avoid tracked since March 5th with slips on the
+// 8th (two) and the 10th
+func noSugar() Activity {
+	return Activity{
+		Name:     "No sugar",
+		Polarity: PolarityAvoid,
+		Created:  "2025-03-05",
+		Dates:    []string{"2025-03-08", "2025-03-10", "2025-03-08"},
+	}
+}
+
+func TestTrackingStart(t *testing.T) {
+	earlier := noSugar()
+	earlier.Dates = append(earlier.Dates, "2025-03-01")
+	uncreated := noSugar()
+	uncreated.Created = ""
+
+	for name, test := range map[string]struct {
+		activity Activity
+		want     string
+	}{
+		"created":                {noSugar(), "2025-03-05"},
+		"entry before creation":  {earlier, "2025-03-01"},
+		"no creation date":       {uncreated, "2025-03-08"},
+		"no creation or entries": {Activity{Polarity: PolarityAvoid}, ""},
+	} {
+		if got := test.activity.TrackingStart(); got != test.want {
+			t.Errorf("%s: TrackingStart = %q, want %q", name, got, test.want)
+		}
+	}
+}
+
+func TestCleanDays(t *testing.T) {
+	if clean, tracked := noSugar().CleanDays(polarityToday); clean != 6 || tracked != 8 {
+		t.Errorf("CleanDays = %d of %d, want 6 of 8", clean, tracked)
+	}
+
+	slipToday := noSugar()
+	slipToday.Dates = append(slipToday.Dates, "2025-03-12")
+	if clean, tracked := slipToday.CleanDays(polarityToday); clean != 5 || tracked != 8 {
+		t.Errorf("CleanDays with a slip today = %d of %d, want 5 of 8", clean, tracked)
+	}
+
+	future := noSugar()
+	future.Created = "2025-03-20"
+	future.Dates = nil
+	if clean, tracked := future.CleanDays(polarityToday); clean != 0 || tracked != 0 {
+		t.Errorf("CleanDays before tracking starts = %d of %d, want 0 of 0", clean, tracked)
+	}
+	if clean, tracked := (Activity{Polarity: PolarityAvoid}).CleanDays(polarityToday); clean != 0 || tracked != 0 {
+		t.Errorf("CleanDays of an untracked habit = %d of %d, want 0 of 0", clean, tracked)
+	}
+}
+
+func TestCleanStreakEndingOn(t *testing.T) {
+	activity := noSugar()
+	counts := NewDateCounts(activity.Dates)
+	day := func(date string) time.Time {
+		parsed, _ := time.Parse(DateFormat, date)
+		return parsed
+	}
+
+	for _, test := range []struct {
+		name  string
+		end   string
+		start string
+		want  int
+	}{
+		{"clean today", "2025-03-12", "2025-03-05", 2},
+		{"slip breaks the streak", "2025-03-10", "2025-03-05", 0},
+		{"stops at the tracking start", "2025-03-07", "2025-03-05", 3},
+		{"no tracking start", "2025-03-12", "", 0},
+	} {
+		if got := counts.CleanStreakEndingOn(day(test.end), test.start); got != test.want {
+			t.Errorf("%s: CleanStreakEndingOn(%s) = %d, want %d", test.name, test.end, got, test.want)
+		}
+	}
+
+	slipToday := NewDateCounts(append(activity.Dates, "2025-03-12"))
+	if got := slipToday.CleanStreakEndingOn(day("2025-03-12"), "2025-03-05"); got != 0 {
+		t.Errorf("CleanStreakEndingOn with a slip today = %d, want 0", got)
+	}
+}
+
+func TestMetOn(t *testing.T) {
+	avoid := noSugar().Track(polarityToday)
+	build := Activity{TargetPerDay: 3, Created: "2025-03-01"}.Track(polarityToday)
+	single := Activity{Created: "2025-03-01"}.Track(polarityToday)
+
+	for _, test := range []struct {
+		name    string
+		tracked TrackedDays
+		date    string
+		count   int
+		want    bool
+	}{
+		{"avoid: clean day", avoid, "2025-03-09", 0, true},
+		{"avoid: clean today", avoid, "2025-03-12", 0, true},
+		{"avoid: slip", avoid, "2025-03-08", 2, false},
+		{"avoid: before tracking start", avoid, "2025-03-04", 0, false},
+		{"avoid: after today", avoid, "2025-03-13", 0, false},
+		{"build: below the target", build, "2025-03-09", 2, false},
+		{"build: at the target", build, "2025-03-09", 3, true},
+		{"build: above the target", build, "2025-03-09", 4, true},
+		{"build: no entries", build, "2025-03-09", 0, false},
+		{"build: no target set", single, "2025-03-09", 1, true},
+	} {
+		if got := test.tracked.MetOn(test.date, test.count); got != test.want {
+			t.Errorf("%s: MetOn(%s, %d) = %t, want %t", test.name, test.date, test.count, got, test.want)
+		}
+	}
+
+	if build.IsCleanDay("2025-03-09", 0) {
+		t.Errorf("a day without entries is clean for a habit to build")
+	}
+}
@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultProfile is used when no other profile is selected. Its data and
+// config live directly in the hab directory, where they always have.
+const DefaultProfile = "default"
+
+// Profile names are used as directory names
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// selectedProfile is the profile chosen with UseProfile, if any
+var selectedProfile string
+
+// Profile is a named set of habits with its own data file and config
+type Profile struct {
+	Name string
+	Dir  string
+}
+
+// UseProfile selects the profile that data and config paths resolve to for
+// the rest of the process, overriding HAB_PROFILE and the config file
+func UseProfile(name string) {
+	selectedProfile = name
+}
+
+// ActiveProfile returns the profile in use: the one passed to UseProfile,
+// else HAB_PROFILE, else the one remembered in the main config file
+func ActiveProfile() string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	if profile := os.Getenv("HAB_PROFILE"); profile != "" {
+		return profile
+	}
+	if config, err := readConfig(mainConfigPath()); err == nil && config.Profile != "" {
+		return config.Profile
+	}
+	return DefaultProfile
+}
+
+// ValidateProfileName checks that a profile name can be used as a directory
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s', use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// profileDir returns the directory holding a profile's data and config
+func profileDir(name string) string {
+	if name == DefaultProfile {
+		return getHabDir()
+	}
+	return filepath.Join(getHabDir(), "profiles", name)
+}
+
+// ProfileExists reports whether a profile has been created
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(profileDir(name))
+	return err == nil && info.IsDir()
+}
+
+// CheckActiveProfile returns an error if the active profile doesn't exist
+func CheckActiveProfile() error {
+	name := ActiveProfile()
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist, create it with 'hab profile create %s'", name, name)
+	}
+	return nil
+}
+
+// ListProfiles returns the default profile followed by the others by name
+func ListProfiles() ([]Profile, error) {
+	profiles := []Profile{{Name: DefaultProfile, Dir: profileDir(DefaultProfile)}}
+
+	entries, err := os.ReadDir(filepath.Join(getHabDir(), "profiles"))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfileName(entry.Name()) == nil && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profiles = append(profiles, Profile{Name: name, Dir: profileDir(name)})
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates an empty profile
+func CreateProfile(name string) (Profile, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return Profile{}, err
+	}
+	if ProfileExists(name) {
+		return Profile{}, fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	dir := profileDir(name)
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0755); err != nil {
+		return Profile{}, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return Profile{Name: name, Dir: dir}, nil
+}
+
+// DeleteProfile removes a profile and all its data. The default profile and
+// the one in use can't be deleted.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+	if name == ActiveProfile() {
+		return fmt.Errorf("profile '%s' is in use, switch to another profile first", name)
+	}
+
+	if err := os.RemoveAll(profileDir(name)); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	return nil
+}
+
+// SetDefaultProfile remembers the profile to use when none is selected
+func SetDefaultProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+
+	config, err := readConfig(mainConfigPath())
+	if err != nil {
+		return err
+	}
+	config.Profile = name
+	if name == DefaultProfile {
+		config.Profile = ""
+	}
+	return config.writeTo(mainConfigPath())
+}
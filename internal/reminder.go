@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Clock supplies the current time, so reminders can be driven by a fake one
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by the real wall clock
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Reminder sends notifications for habits whose daily target isn't met yet
+// when one of their reminder times passes
+type Reminder struct {
+	load     func() (*HabitManager, error)
+	dataFile string
+	clock    Clock
+	notifier Notifier
+
+	hm        *HabitManager
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewReminder creates a reminder for the default data file. Reminder times
+// that passed before the first check are not announced.
+func NewReminder(clock Clock, notifier Notifier) *Reminder {
+	return &Reminder{
+		load: func() (*HabitManager, error) {
+			hm := NewHabitManager()
+			return hm, hm.Load()
+		},
+		dataFile:  defaultStorePath(),
+		clock:     clock,
+		notifier:  notifier,
+		lastCheck: clock.Now(),
+	}
+}
+
+// Run checks for due reminders every interval until ctx is cancelled
+func (r *Reminder) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if err := r.reload(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Check(); err != nil {
+				fmt.Fprintf(os.Stderr, "Reminder check failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// Check reloads the data file if it changed and notifies for every reminder
+// time passed since the previous check
+func (r *Reminder) Check() error {
+	if err := r.reloadIfChanged(); err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	since := r.lastCheck
+	r.lastCheck = now
+
+	for _, key := range r.hm.SortedKeys() {
+		activity, _ := r.hm.GetActivity(key)
+		if activity.IsAvoid() {
+			continue // there's nothing to log for a habit to avoid
+		}
+		for i, reminderTime := range activity.Reminders {
+			due, err := reminderAt(now, reminderTime)
+			if err != nil || !due.After(since) || due.After(now) {
+				continue
+			}
+
+			done := r.hm.CountOn(key, now.Format(DateFormat))
+			expected := expectedByReminder(activity, i+1)
+			if done >= expected {
+				continue
+			}
+
+			title := fmt.Sprintf("Time for %s", activity.Name)
+			body := fmt.Sprintf("Logged %d of %d today. Run: hab %s", done, max(1, activity.TargetPerDay), key)
+			if err := r.notifier.Notify(title, body); err != nil {
+				return fmt.Errorf("failed to notify for '%s': %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reloadIfChanged reloads habits when the data file's modification time moves
+func (r *Reminder) reloadIfChanged() error {
+	info, err := os.Stat(r.dataFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat data file: %w", err)
+	}
+	if r.hm != nil && info.ModTime().Equal(r.modTime) {
+		return nil
+	}
+	return r.reload()
+}
+
+// reload reads habits from disk and records the file's modification time
+func (r *Reminder) reload() error {
+	hm, err := r.load()
+	if err != nil {
+		return err
+	}
+	r.hm = hm
+
+	if info, err := os.Stat(r.dataFile); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// reminderAt returns the given HH:MM time on now's day
+func reminderAt(now time.Time, hhmm string) (time.Time, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+}
+
+// expectedByReminder returns how many entries should be logged by the nth
+// reminder, spreading the daily target evenly across the habit's reminders
+func expectedByReminder(activity Activity, nth int) int {
+	target := max(1, activity.TargetPerDay)
+	reminders := max(1, len(activity.Reminders))
+	return (target*nth + reminders - 1) / reminders
+}
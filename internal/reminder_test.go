@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when the test sets it
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// fakeNotifier records notifications instead of sending them
+type fakeNotifier struct {
+	bodies []string
+}
+
+func (n *fakeNotifier) Notify(title, body string) error {
+	n.bodies = append(n.bodies, body)
+	return nil
+}
+
+// newTestReminder creates a habit logged three times a day with three
+// reminders, and a reminder watching its data file from clock's time
+func newTestReminder(t *testing.T, clock *fakeClock) (*Reminder, *fakeNotifier) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HAB_DATA_FILE", filepath.Join(dir, "activities.json"))
+	t.Setenv("HAB_CONFIG_FILE", filepath.Join(dir, "config.json"))
+	t.Setenv("HAB_STORAGE", "")
+	t.Setenv("HAB_PROFILE", "")
+
+	hm := NewHabitManager()
+	err := hm.Mutate(func(tx *Tx) error {
+		if err := tx.CreateActivity("water", "Water", "blue", 3); err != nil {
+			return err
+		}
+		return tx.SetReminders("water", []string{"08:00", "12:00", "18:00"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifier := &fakeNotifier{}
+	return NewReminder(clock, notifier), notifier
+}
+
+// logEntry adds an entry the way another hab process would, moving the data
+// file's modification time so the change is visible even on coarse clocks
+func logEntry(t *testing.T, date string, modTime time.Time) {
+	t.Helper()
+	hm := NewHabitManager()
+	if err := hm.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := hm.AddEntry("water", date); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(hm.DataFile(), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkAt moves the clock and runs a check, returning the new notifications
+func checkAt(t *testing.T, r *Reminder, clock *fakeClock, notifier *fakeNotifier, at time.Time) []string {
+	t.Helper()
+	clock.now = at
+	sent := len(notifier.bodies)
+	if err := r.Check(); err != nil {
+		t.Fatalf("Check at %s: %v", at.Format("15:04"), err)
+	}
+	return notifier.bodies[sent:]
+}
+
+func TestExpectedByReminder(t *testing.T) {
+	for _, test := range []struct {
+		target    int
+		reminders int
+		want      []int
+	}{
+		{target: 1, reminders: 1, want: []int{1}},
+		{target: 3, reminders: 3, want: []int{1, 2, 3}},
+		{target: 2, reminders: 3, want: []int{1, 2, 2}},
+		{target: 4, reminders: 2, want: []int{2, 4}},
+	} {
+		activity := Activity{TargetPerDay: test.target, Reminders: make([]string, test.reminders)}
+		for i, want := range test.want {
+			if got := expectedByReminder(activity, i+1); got != want {
+				t.Errorf("target %d over %d reminders: reminder %d expects %d, want %d",
+					test.target, test.reminders, i+1, got, want)
+			}
+		}
+	}
+}
+
+func TestReminderSpreadsTarget(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	clock := &fakeClock{now: day.Add(7 * time.Hour)}
+	r, notifier := newTestReminder(t, clock)
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing logged by the first reminder, which expects one entry
+	if sent := checkAt(t, r, clock, notifier, day.Add(8*time.Hour+time.Minute)); len(sent) != 1 || sent[0] != "Logged 0 of 3 today. Run: hab water" {
+		t.Errorf("08:01 notifications = %q", sent)
+	}
+
+	// One entry is enough until the second reminder, which expects two
+	logEntry(t, "2024-03-04", day.Add(9*time.Hour))
+	if sent := checkAt(t, r, clock, notifier, day.Add(11*time.Hour)); len(sent) != 0 {
+		t.Errorf("11:00 notifications = %q, want none", sent)
+	}
+	if sent := checkAt(t, r, clock, notifier, day.Add(12*time.Hour+time.Minute)); len(sent) != 1 || sent[0] != "Logged 1 of 3 today. Run: hab water" {
+		t.Errorf("12:01 notifications = %q", sent)
+	}
+
+	// Each reminder time is only announced once
+	if sent := checkAt(t, r, clock, notifier, day.Add(13*time.Hour)); len(sent) != 0 {
+		t.Errorf("13:00 notifications = %q, want none", sent)
+	}
+}
+
+func TestReminderReloadsChangedDataFile(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	clock := &fakeClock{now: day.Add(7 * time.Hour)}
+	r, notifier := newTestReminder(t, clock)
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Meeting the whole target elsewhere silences the later reminders
+	logEntry(t, "2024-03-04", day.Add(7*time.Hour+time.Minute))
+	logEntry(t, "2024-03-04", day.Add(7*time.Hour+2*time.Minute))
+	logEntry(t, "2024-03-04", day.Add(7*time.Hour+3*time.Minute))
+	if sent := checkAt(t, r, clock, notifier, day.Add(19*time.Hour)); len(sent) != 0 {
+		t.Errorf("notifications after the target was met = %q, want none", sent)
+	}
+	if done := r.hm.CountOn("water", "2024-03-04"); done != 3 {
+		t.Errorf("reloaded count = %d, want 3", done)
+	}
+}
+
+func TestReminderKeepsDataWhenFileUnchanged(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	clock := &fakeClock{now: day.Add(7 * time.Hour)}
+	r, _ := newTestReminder(t, clock)
+	if err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	load := r.load
+	r.load = func() (*HabitManager, error) {
+		loads++
+		return load()
+	}
+
+	clock.now = day.Add(8 * time.Hour)
+	if err := r.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 0 {
+		t.Errorf("data file reloaded %d times without changing", loads)
+	}
+}
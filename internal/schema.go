@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// DataVersion is the data file version this build reads and writes
+const DataVersion = 4
+
+// dataMigration upgrades a decoded data file from one version to the next
+type dataMigration struct {
+	from        int
+	description string
+	migrate     func(raw map[string]any) error
+}
+
+// dataMigrations are run in order by Load; each one moves a file up one
+// version. Files written before versioning existed are version 0.
+var dataMigrations = []dataMigration{
+	{
+		from:        0,
+		description: "add version key and replace null date lists",
+		migrate: func(raw map[string]any) error {
+			activities, _ := raw["activities"].(map[string]any)
+			for _, value := range activities {
+				if activity, ok := value.(map[string]any); ok && activity["dates"] == nil {
+					activity["dates"] = []any{}
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// Older versions would drop the authors when saving
+		from:        1,
+		description: "allow entries to record who logged them",
+		migrate:     func(raw map[string]any) error { return nil },
+	},
+	{
+		// Older versions would drop the goals when saving
+		from:        2,
+		description: "add goals beside activities",
+		migrate:     func(raw map[string]any) error { return nil },
+	},
+	{
+		// Older versions would drop the polarity and treat habits to avoid
+		// as habits to build
+		from:        3,
+		description: "add habits to avoid and creation dates",
+		migrate:     func(raw map[string]any) error { return nil },
+	},
+}
+
+// dataVersion reads the version key of a plaintext data file
+func dataVersion(contents []byte) (int, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(contents, &header); err != nil {
+		return 0, fmt.Errorf("failed to parse data file: %w", err)
+	}
+	if header.Version > DataVersion {
+		return 0, fmt.Errorf("data file version %d is newer than this version of hab supports (%d), upgrade hab to read it",
+			header.Version, DataVersion)
+	}
+	return header.Version, nil
+}
+
+// migrateStep runs the migration for a file's current version and returns
+// the upgraded file and its new version
+func migrateStep(contents []byte) ([]byte, int, error) {
+	version, err := dataVersion(contents)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, migration := range dataMigrations {
+		if migration.from != version {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+		var raw map[string]any
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse data file: %w", err)
+		}
+
+		if err := migration.migrate(raw); err != nil {
+			return nil, 0, fmt.Errorf("failed to migrate data file from version %d (%s): %w", version, migration.description, err)
+		}
+		raw["version"] = version + 1
+
+		migrated, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal migrated data: %w", err)
+		}
+		return migrated, version + 1, nil
+	}
+
+	return nil, 0, fmt.Errorf("no migration from data file version %d", version)
+}
+
+// migrateData upgrades a plaintext data file to DataVersion in memory
+func migrateData(contents []byte) ([]byte, error) {
+	version, err := dataVersion(contents)
+	for err == nil && version < DataVersion {
+		contents, version, err = migrateStep(contents)
+	}
+	return contents, err
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestMigrateStep runs each migration on testdata/migrations/vN.json and
+// compares the result with vN.golden.json
+func TestMigrateStep(t *testing.T) {
+	for _, migration := range dataMigrations {
+		from := migration.from
+		t.Run(fmt.Sprintf("v%d", from), func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "migrations", fmt.Sprintf("v%d.json", from)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			migrated, version, err := migrateStep(input)
+			if err != nil {
+				t.Fatalf("migrateStep: %v", err)
+			}
+			if version != from+1 {
+				t.Errorf("version = %d, want %d", version, from+1)
+			}
+
+			golden := filepath.Join("testdata", "migrations", fmt.Sprintf("v%d.golden.json", from))
+			if *updateGolden {
+				if err := os.WriteFile(golden, migrated, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(migrated, want) {
+				t.Errorf("migrateStep from v%d =\n%s\nwant\n%s", from, migrated, want)
+			}
+		})
+	}
+}
+
+func TestMigrateDataToCurrentVersion(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "migrations", "v0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := migrateData(input)
+	if err != nil {
+		t.Fatalf("migrateData: %v", err)
+	}
+	if version, err := dataVersion(migrated); err != nil || version != DataVersion {
+		t.Errorf("dataVersion = %d, %v, want %d", version, err, DataVersion)
+	}
+}
+
+func TestNewerDataVersionRefused(t *testing.T) {
+	newer := []byte(fmt.Sprintf(`{"version": %d, "activities": {}}`, DataVersion+1))
+
+	for name, migrate := range map[string]func([]byte) error{
+		"migrateStep": func(contents []byte) error { _, _, err := migrateStep(contents); return err },
+		"migrateData": func(contents []byte) error { _, err := migrateData(contents); return err },
+		"ParseActivitiesData": func(contents []byte) error {
+			_, err := ParseActivitiesData(contents)
+			return err
+		},
+	} {
+		if err := migrate(newer); err == nil || !strings.Contains(err.Error(), "upgrade hab") {
+			t.Errorf("%s = %v, want an error asking to upgrade hab", name, err)
+		}
+	}
+}
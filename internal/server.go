@@ -0,0 +1,279 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server exposes habit data over a small JSON HTTP API
+type Server struct {
+	mu    sync.Mutex
+	token string
+}
+
+// habitResponse is the JSON shape of a single habit returned by the API
+type habitResponse struct {
+	Key   string                 `json:"key"`
+	Habit Activity               `json:"habit"`
+	Stats map[string]interface{} `json:"stats"`
+}
+
+// habitRequest is the JSON body accepted when creating or updating a habit
+type habitRequest struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	Color        string `json:"color"`
+	TargetPerDay int    `json:"target_per_day"`
+	Polarity     string `json:"polarity"`
+}
+
+// entryRequest is the JSON body accepted when adding an entry
+type entryRequest struct {
+	Date string `json:"date"`
+	By   string `json:"by"` // who logged it, optional
+}
+
+// NewServer creates an API server. When token is non-empty every request
+// must carry it as a bearer token.
+func NewServer(token string) *Server {
+	return &Server{token: token}
+}
+
+// Handler returns the HTTP handler serving the API routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /habits", s.withManager(s.listHabits))
+	mux.HandleFunc("POST /habits", s.withManager(s.createHabit))
+	mux.HandleFunc("GET /habits/{key}", s.withManager(s.getHabit))
+	mux.HandleFunc("PATCH /habits/{key}", s.withManager(s.updateHabit))
+	mux.HandleFunc("DELETE /habits/{key}", s.withManager(s.deleteHabit))
+	mux.HandleFunc("GET /habits/{key}/stats", s.withManager(s.getStats))
+	mux.HandleFunc("POST /habits/{key}/entries", s.withManager(s.addEntry))
+	mux.HandleFunc("DELETE /habits/{key}/entries/{date}", s.withManager(s.removeEntry))
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withManager serialises access to the data file and hands each request a
+// freshly loaded manager, so changes made by the CLI are picked up
+func (s *Server) withManager(handle func(*HabitManager, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		hm := NewHabitManager()
+		if err := hm.Load(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		handle(hm, w, r)
+	}
+}
+
+func (s *Server) listHabits(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	habits := []habitResponse{}
+	for _, key := range hm.SortedKeys() {
+		activity, _ := hm.GetActivity(key)
+		stats, _ := hm.GetStats(key)
+		habits = append(habits, habitResponse{Key: key, Habit: activity, Stats: stats})
+	}
+	writeJSON(w, http.StatusOK, habits)
+}
+
+func (s *Server) createHabit(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	var req habitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Key == "" {
+		writeError(w, http.StatusBadRequest, "key is required")
+		return
+	}
+	if req.Name == "" {
+		req.Name = strings.Title(strings.ReplaceAll(req.Key, "_", " "))
+	}
+	if req.Color == "" {
+		req.Color = "green"
+	}
+	if !IsValidColor(req.Color) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid color '%s'", req.Color))
+		return
+	}
+	if _, exists := hm.GetActivity(req.Key); exists {
+		writeError(w, http.StatusConflict, fmt.Sprintf("activity '%s' already exists", req.Key))
+		return
+	}
+
+	if req.Polarity != PolarityBuild && req.Polarity != PolarityAvoid {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid polarity '%s'", req.Polarity))
+		return
+	}
+
+	err := hm.Mutate(func(tx *Tx) error {
+		if err := tx.CreateActivity(req.Key, req.Name, req.Color, req.TargetPerDay); err != nil {
+			return err
+		}
+		return tx.SetPolarity(req.Key, req.Polarity)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeHabit(hm, w, http.StatusCreated, req.Key)
+}
+
+func (s *Server) getHabit(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+	s.writeHabit(hm, w, http.StatusOK, key)
+}
+
+func (s *Server) updateHabit(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+
+	var req habitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Color != "" && !IsValidColor(req.Color) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid color '%s'", req.Color))
+		return
+	}
+
+	if err := hm.UpdateActivity(key, req.Name, req.Color, req.TargetPerDay); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeHabit(hm, w, http.StatusOK, key)
+}
+
+func (s *Server) deleteHabit(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+
+	if err := hm.DeleteActivity(key); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getStats(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+
+	stats, err := hm.GetStats(key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) addEntry(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+
+	// An empty body logs an entry for today
+	var req entryRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+	}
+	date, err := ParseDate(req.Date, time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The habit and date were checked above, so this can only fail to save
+	if err := hm.AddEntries(key, []string{date.Format(DateFormat)}, strings.TrimSpace(req.By)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeHabit(hm, w, http.StatusCreated, key)
+}
+
+func (s *Server) removeEntry(hm *HabitManager, w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !requireHabit(hm, w, key) {
+		return
+	}
+
+	date, err := ParseDate(r.PathValue("date"), time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ?by= removes only that person's entry
+	removed, err := hm.RemoveEntries(key, []string{date.Format(DateFormat)}, r.URL.Query().Get("by"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(removed) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no entry to remove on %s", date.Format(DateFormat)))
+		return
+	}
+	s.writeHabit(hm, w, http.StatusOK, key)
+}
+
+// writeHabit responds with a habit and its current statistics
+func (s *Server) writeHabit(hm *HabitManager, w http.ResponseWriter, status int, key string) {
+	activity, _ := hm.GetActivity(key)
+	stats, _ := hm.GetStats(key)
+	writeJSON(w, status, habitResponse{Key: key, Habit: activity, Stats: stats})
+}
+
+// requireHabit responds with 404 and returns false when the habit is missing
+func requireHabit(hm *HabitManager, w http.ResponseWriter, key string) bool {
+	if _, exists := hm.GetActivity(key); !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("activity '%s' does not exist", key))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,258 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testToken = "s3cret"
+
+// newTestServer serves the API over a data file in a temporary directory
+func newTestServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "activities.json")
+	t.Setenv("HAB_DATA_FILE", dataFile)
+	t.Setenv("HAB_CONFIG_FILE", filepath.Join(dir, "config.json"))
+	t.Setenv("HAB_STORAGE", "")
+	t.Setenv("HAB_PROFILE", "")
+
+	server := httptest.NewServer(NewServer(testToken).Handler())
+	t.Cleanup(server.Close)
+	return server, dataFile
+}
+
+// request calls the API with the test token and decodes the JSON response
+// into out when it is non-nil
+func request(t *testing.T, server *httptest.Server, method, path, body string, out any) int {
+	t.Helper()
+	return requestWithToken(t, server, testToken, method, path, body, out)
+}
+
+func requestWithToken(t *testing.T, server *httptest.Server, token, method, path, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	contents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(contents, out); err != nil {
+			t.Fatalf("%s %s: failed to decode %q: %v", method, path, contents, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// savedDates reads a habit's entries back from the data file
+func savedDates(t *testing.T, dataFile, key string) []string {
+	t.Helper()
+	contents, err := os.ReadFile(dataFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ParseActivitiesData(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data.Activities[key].Dates
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	for name, token := range map[string]string{"missing": "", "wrong": "guess"} {
+		var body map[string]string
+		if status := requestWithToken(t, server, token, "GET", "/habits", "", &body); status != http.StatusUnauthorized {
+			t.Errorf("%s token: status = %d, want %d", name, status, http.StatusUnauthorized)
+		}
+		if body["error"] == "" {
+			t.Errorf("%s token: no error message in %v", name, body)
+		}
+	}
+
+	if status := request(t, server, "GET", "/habits", "", nil); status != http.StatusOK {
+		t.Errorf("right token: status = %d, want %d", status, http.StatusOK)
+	}
+
+	// The token alone, without the Bearer scheme, is not enough
+	for _, header := range []string{testToken, "Basic " + testToken} {
+		req, err := http.NewRequest("GET", server.URL+"/habits", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", header)
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status = %d, want %d", header, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestServerHabits(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var created habitResponse
+	status := request(t, server, "POST", "/habits", `{"key": "morning_run", "color": "blue", "target_per_day": 2}`, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("create: status = %d, want %d", status, http.StatusCreated)
+	}
+	if created.Key != "morning_run" || created.Habit.Name != "Morning Run" || created.Habit.TargetPerDay != 2 {
+		t.Errorf("create = %+v", created)
+	}
+
+	var conflict map[string]string
+	if status := request(t, server, "POST", "/habits", `{"key": "morning_run"}`, &conflict); status != http.StatusConflict {
+		t.Errorf("duplicate create: status = %d, want %d", status, http.StatusConflict)
+	}
+	for name, body := range map[string]string{
+		"invalid JSON":     `{`,
+		"missing key":      `{"name": "Nameless"}`,
+		"invalid color":    `{"key": "x", "color": "plaid"}`,
+		"invalid polarity": `{"key": "x", "polarity": "sideways"}`,
+	} {
+		if status := request(t, server, "POST", "/habits", body, nil); status != http.StatusBadRequest {
+			t.Errorf("create with %s: status = %d, want %d", name, status, http.StatusBadRequest)
+		}
+	}
+
+	var list []habitResponse
+	if status := request(t, server, "GET", "/habits", "", &list); status != http.StatusOK || len(list) != 1 || list[0].Key != "morning_run" {
+		t.Errorf("list: status = %d, habits = %+v", status, list)
+	}
+
+	var got habitResponse
+	if status := request(t, server, "GET", "/habits/morning_run", "", &got); status != http.StatusOK || got.Habit.Color != "blue" {
+		t.Errorf("get: status = %d, habit = %+v", status, got)
+	}
+
+	var updated habitResponse
+	status = request(t, server, "PATCH", "/habits/morning_run", `{"name": "Run", "color": "red"}`, &updated)
+	if status != http.StatusOK || updated.Habit.Name != "Run" || updated.Habit.Color != "red" {
+		t.Errorf("update: status = %d, habit = %+v", status, updated)
+	}
+	if status := request(t, server, "PATCH", "/habits/morning_run", `{"color": "plaid"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("update with invalid color: status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var stats map[string]any
+	if status := request(t, server, "GET", "/habits/morning_run/stats", "", &stats); status != http.StatusOK || stats["name"] != "Run" {
+		t.Errorf("stats: status = %d, stats = %v", status, stats)
+	}
+
+	if status := request(t, server, "DELETE", "/habits/morning_run", "", nil); status != http.StatusNoContent {
+		t.Errorf("delete: status = %d, want %d", status, http.StatusNoContent)
+	}
+	if status := request(t, server, "GET", "/habits/morning_run", "", nil); status != http.StatusNotFound {
+		t.Errorf("get after delete: status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestServerUnknownHabit(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	for _, route := range []struct{ method, path, body string }{
+		{"GET", "/habits/nope", ""},
+		{"PATCH", "/habits/nope", `{"name": "Nope"}`},
+		{"DELETE", "/habits/nope", ""},
+		{"GET", "/habits/nope/stats", ""},
+		{"POST", "/habits/nope/entries", `{"date": "2024-01-01"}`},
+		{"DELETE", "/habits/nope/entries/2024-01-01", ""},
+	} {
+		var body map[string]string
+		if status := request(t, server, route.method, route.path, route.body, &body); status != http.StatusNotFound {
+			t.Errorf("%s %s: status = %d, want %d", route.method, route.path, status, http.StatusNotFound)
+		}
+		if !strings.Contains(body["error"], "does not exist") {
+			t.Errorf("%s %s: error = %q", route.method, route.path, body["error"])
+		}
+	}
+}
+
+func TestServerEntryRoundTrip(t *testing.T) {
+	server, dataFile := newTestServer(t)
+	if status := request(t, server, "POST", "/habits", `{"key": "exercise"}`, nil); status != http.StatusCreated {
+		t.Fatalf("create: status = %d", status)
+	}
+
+	var added habitResponse
+	status := request(t, server, "POST", "/habits/exercise/entries", `{"date": "2024-01-02", "by": "alice"}`, &added)
+	if status != http.StatusCreated {
+		t.Fatalf("add entry: status = %d, want %d", status, http.StatusCreated)
+	}
+	if len(added.Habit.Dates) != 1 || added.Habit.Dates[0] != "2024-01-02" {
+		t.Errorf("add entry: dates = %v", added.Habit.Dates)
+	}
+	if dates := savedDates(t, dataFile, "exercise"); len(dates) != 1 || dates[0] != "2024-01-02" {
+		t.Errorf("saved dates after add = %v", dates)
+	}
+
+	if status := request(t, server, "POST", "/habits/exercise/entries", `{"date": "someday"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("add entry with invalid date: status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var removed habitResponse
+	if status := request(t, server, "DELETE", "/habits/exercise/entries/2024-01-02", "", &removed); status != http.StatusOK {
+		t.Fatalf("remove entry: status = %d, want %d", status, http.StatusOK)
+	}
+	if len(removed.Habit.Dates) != 0 {
+		t.Errorf("remove entry: dates = %v", removed.Habit.Dates)
+	}
+	if dates := savedDates(t, dataFile, "exercise"); len(dates) != 0 {
+		t.Errorf("saved dates after remove = %v", dates)
+	}
+
+	if status := request(t, server, "DELETE", "/habits/exercise/entries/2024-01-02", "", nil); status != http.StatusNotFound {
+		t.Errorf("remove missing entry: status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestServerSaveFailure(t *testing.T) {
+	server, dataFile := newTestServer(t)
+	if status := request(t, server, "POST", "/habits", `{"key": "exercise"}`, nil); status != http.StatusCreated {
+		t.Fatalf("create: status = %d", status)
+	}
+
+	// A file where the backup directory should be stops the next save
+	backups := filepath.Join(filepath.Dir(dataFile), "backups")
+	if err := os.RemoveAll(backups); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(backups, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var body map[string]string
+	if status := request(t, server, "POST", "/habits/exercise/entries", `{"date": "2024-01-02"}`, &body); status != http.StatusInternalServerError {
+		t.Errorf("add entry that can't be saved: status = %d, want %d", status, http.StatusInternalServerError)
+	}
+	if body["error"] == "" {
+		t.Errorf("no error message in %v", body)
+	}
+	if dates := savedDates(t, dataFile, "exercise"); len(dates) != 0 {
+		t.Errorf("saved dates after a failed save = %v", dates)
+	}
+}
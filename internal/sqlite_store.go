@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver, no cgo needed
+)
+
+// sqliteMigrations creates and upgrades the schema. Each statement runs
+// once, in order, and PRAGMA user_version records how many have run.
+var sqliteMigrations = []string{
+	`CREATE TABLE activities (
+		key            TEXT PRIMARY KEY,
+		name           TEXT NOT NULL,
+		color          TEXT NOT NULL,
+		target_per_day INTEGER NOT NULL DEFAULT 0,
+		reminders      TEXT
+	);
+	CREATE TABLE entries (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		activity_key TEXT NOT NULL,
+		date         TEXT NOT NULL
+	);
+	CREATE INDEX entries_by_date ON entries (activity_key, date);`,
+	`ALTER TABLE entries ADD COLUMN author TEXT;`,
+	`CREATE TABLE goals (
+		id         TEXT PRIMARY KEY,
+		habit      TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		target     INTEGER NOT NULL,
+		start_date TEXT NOT NULL,
+		end_date   TEXT NOT NULL
+	);`,
+	`ALTER TABLE activities ADD COLUMN polarity TEXT NOT NULL DEFAULT '';
+	ALTER TABLE activities ADD COLUMN created TEXT NOT NULL DEFAULT '';`,
+}
+
+// SQLiteStore keeps habit data in a SQLite database. Entries are rows, so
+// adding, removing and querying them doesn't rewrite the whole file.
+type SQLiteStore struct {
+	path string
+}
+
+// NewSQLiteStore creates a store for the SQLite database at path
+func NewSQLiteStore(path string) *SQLiteStore {
+	return &SQLiteStore{path: path}
+}
+
+// Path returns the database file path
+func (s *SQLiteStore) Path() string {
+	return s.path
+}
+
+// open connects to the database, creating and upgrading its schema as
+// needed. Callers close the connection when done.
+func (s *SQLiteStore) open() (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+s.path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrateSQLite runs the schema migrations the database hasn't seen yet
+func migrateSQLite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	// Saving would drop whatever a newer schema added, so don't touch it
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than this version of hab supports (%d), upgrade hab to read it",
+			version, len(sqliteMigrations))
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate database to version %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate database to version %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to migrate database to version %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Load reads every activity and its entries in the order they were logged
+func (s *SQLiteStore) Load() (*ActivitiesData, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	data := &ActivitiesData{Activities: make(map[string]Activity)}
+
+	rows, err := db.Query(`SELECT key, name, color, target_per_day, reminders, polarity, created FROM activities`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read activities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var reminders sql.NullString
+		activity := Activity{Dates: []string{}}
+		if err := rows.Scan(&key, &activity.Name, &activity.Color, &activity.TargetPerDay, &reminders,
+			&activity.Polarity, &activity.Created); err != nil {
+			return nil, fmt.Errorf("failed to read activities: %w", err)
+		}
+		if reminders.Valid {
+			if err := json.Unmarshal([]byte(reminders.String), &activity.Reminders); err != nil {
+				return nil, fmt.Errorf("failed to parse reminders of '%s': %w", key, err)
+			}
+		}
+		data.Activities[key] = activity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activities: %w", err)
+	}
+
+	entries, err := db.Query(`SELECT activity_key, date, author FROM entries ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entries: %w", err)
+	}
+	defer entries.Close()
+
+	for entries.Next() {
+		var key, date string
+		var author sql.NullString
+		if err := entries.Scan(&key, &date, &author); err != nil {
+			return nil, fmt.Errorf("failed to read entries: %w", err)
+		}
+		if activity, exists := data.Activities[key]; exists {
+			activity.Dates = append(activity.Dates, date)
+			if author.Valid {
+				if activity.Authors == nil {
+					activity.Authors = make(map[string][]string)
+				}
+				activity.Authors[date] = append(activity.Authors[date], author.String)
+			}
+			data.Activities[key] = activity
+		}
+	}
+	if err := entries.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read entries: %w", err)
+	}
+
+	goals, err := db.Query(`SELECT id, habit, kind, target, start_date, end_date FROM goals`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goals: %w", err)
+	}
+	defer goals.Close()
+
+	for goals.Next() {
+		var id string
+		var goal Goal
+		if err := goals.Scan(&id, &goal.Habit, &goal.Kind, &goal.Target, &goal.Start, &goal.End); err != nil {
+			return nil, fmt.Errorf("failed to read goals: %w", err)
+		}
+		if data.Goals == nil {
+			data.Goals = make(map[string]Goal)
+		}
+		data.Goals[id] = goal
+	}
+	if err := goals.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read goals: %w", err)
+	}
+
+	return data, nil
+}
+
+// Save replaces the database contents with data in one transaction
+func (s *SQLiteStore) Save(data *ActivitiesData) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to write database: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM entries; DELETE FROM activities; DELETE FROM goals`); err != nil {
+		return fmt.Errorf("failed to write database: %w", err)
+	}
+
+	for _, key := range data.sortedKeys() {
+		activity := data.Activities[key]
+
+		var reminders sql.NullString
+		if len(activity.Reminders) > 0 {
+			encoded, err := json.Marshal(activity.Reminders)
+			if err != nil {
+				return fmt.Errorf("failed to encode reminders of '%s': %w", key, err)
+			}
+			reminders = sql.NullString{String: string(encoded), Valid: true}
+		}
+
+		if _, err := tx.Exec(`INSERT INTO activities (key, name, color, target_per_day, reminders, polarity, created)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			key, activity.Name, activity.Color, activity.TargetPerDay, reminders, activity.Polarity, activity.Created); err != nil {
+			return fmt.Errorf("failed to write activity '%s': %w", key, err)
+		}
+
+		// Each day's first entries carry its authors, in order
+		attributed := make(DateCounts)
+		for _, date := range activity.Dates {
+			var author sql.NullString
+			if authors := activity.Authors[date]; attributed[date] < len(authors) {
+				author = sql.NullString{String: authors[attributed[date]], Valid: true}
+				attributed[date]++
+			}
+			if _, err := tx.Exec(`INSERT INTO entries (activity_key, date, author) VALUES (?, ?, ?)`, key, date, author); err != nil {
+				return fmt.Errorf("failed to write entries of '%s': %w", key, err)
+			}
+		}
+	}
+
+	for id, goal := range data.Goals {
+		if _, err := tx.Exec(`INSERT INTO goals (id, habit, kind, target, start_date, end_date) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, goal.Habit, goal.Kind, goal.Target, goal.Start, goal.End); err != nil {
+			return fmt.Errorf("failed to write goal '%s': %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write database: %w", err)
+	}
+	return nil
+}
+
+// List returns all activity keys in alphabetical order
+func (s *SQLiteStore) List() ([]string, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT key FROM activities ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activities: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to list activities: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// AddEntry inserts one entry for an activity
+func (s *SQLiteStore) AddEntry(key, date string) error {
+	if _, err := time.Parse(DateFormat, date); err != nil {
+		return fmt.Errorf("invalid date format '%s', use YYYY-MM-DD", date)
+	}
+
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := requireActivity(db, key); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`INSERT INTO entries (activity_key, date) VALUES (?, ?)`, key, date); err != nil {
+		return fmt.Errorf("failed to add entry: %w", err)
+	}
+	return nil
+}
+
+// RemoveEntry deletes the earliest logged entry for an activity on a date
+func (s *SQLiteStore) RemoveEntry(key, date string) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := requireActivity(db, key); err != nil {
+		return err
+	}
+
+	result, err := db.Exec(`DELETE FROM entries WHERE id = (
+		SELECT id FROM entries WHERE activity_key = ? AND date = ? ORDER BY id LIMIT 1)`, key, date)
+	if err != nil {
+		return fmt.Errorf("failed to remove entry: %w", err)
+	}
+	if removed, _ := result.RowsAffected(); removed == 0 {
+		return fmt.Errorf("date '%s' not found in activity '%s'", date, key)
+	}
+	return nil
+}
+
+// QueryRange returns an activity's entries between start and end inclusive
+func (s *SQLiteStore) QueryRange(key, start, end string) ([]string, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err := requireActivity(db, key); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT date FROM entries
+		WHERE activity_key = ? AND date BETWEEN ? AND ? ORDER BY id`, key, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entries: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []string
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("failed to query entries: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+// requireActivity returns an error unless the activity exists
+func requireActivity(db *sql.DB, key string) error {
+	var exists bool
+	if err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM activities WHERE key = ?)`, key).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to read activity '%s': %w", key, err)
+	}
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSQLiteNewerSchemaRefused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "habits.db")
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`PRAGMA user_version = 99`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := NewSQLiteStore(path).Load(); err == nil || !strings.Contains(err.Error(), "upgrade hab") {
+		t.Errorf("Load = %v, want an error asking to upgrade hab", err)
+	}
+}
+
+// roundTripData has every field a store must keep
+func roundTripData() *ActivitiesData {
+	return &ActivitiesData{
+		Version: DataVersion,
+		Activities: map[string]Activity{
+			"exercise": {
+				Name:         "Exercise",
+				Color:        "green",
+				Dates:        []string{"2025-01-01", "2025-01-02", "2025-01-02", "2025-01-03"},
+				TargetPerDay: 2,
+				Reminders:    []string{"07:30", "19:00"},
+				Authors:      map[string][]string{"2025-01-02": {"alice", "bob"}, "2025-01-03": {"bob"}},
+				Created:      "2024-12-31",
+			},
+			"nosugar": {
+				Name:     "No sugar",
+				Color:    "red",
+				Dates:    []string{"2025-01-02"},
+				Polarity: PolarityAvoid,
+				Created:  "2025-01-01",
+			},
+			"reading": {Name: "Reading", Color: "blue", Dates: []string{}},
+		},
+		Goals: map[string]Goal{
+			"exercise-1": {Habit: "exercise", Kind: GoalTotal, Target: 100, Start: "2025-01-01", End: "2025-12-31"},
+			"nosugar-1":  {Habit: "nosugar", Kind: GoalDaily, Target: 30, Start: "2025-01-01", End: "2025-01-30"},
+		},
+	}
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	original := roundTripData()
+
+	jsonStore := NewJSONStore(filepath.Join(dir, "activities.json"))
+	if err := jsonStore.Save(original); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := jsonStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqliteStore := NewSQLiteStore(filepath.Join(dir, "activities.db"))
+	if err := sqliteStore.Save(fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	fromSQLite, err := sqliteStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromSQLite.Equal(original) {
+		t.Errorf("JSON to SQLite = %+v, want %+v", fromSQLite, original)
+	}
+
+	backStore := NewJSONStore(filepath.Join(dir, "back.json"))
+	if err := backStore.Save(fromSQLite); err != nil {
+		t.Fatal(err)
+	}
+	back, err := backStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !back.Equal(original) {
+		t.Errorf("JSON to SQLite to JSON = %+v, want %+v", back, original)
+	}
+
+	// Saving again replaces rather than adds to what was stored
+	if err := sqliteStore.Save(fromSQLite); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := sqliteStore.Load(); err != nil || !again.Equal(original) {
+		t.Errorf("second save = %+v, %v, want %+v", again, err, original)
+	}
+}
+
+func TestSQLiteEntries(t *testing.T) {
+	store := NewSQLiteStore(filepath.Join(t.TempDir(), "activities.db"))
+	if err := store.Save(roundTripData()); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys, err := store.List(); err != nil || strings.Join(keys, ",") != "exercise,nosugar,reading" {
+		t.Errorf("List = %v, %v", keys, err)
+	}
+	if err := store.AddEntry("reading", "2025-01-05"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RemoveEntry("exercise", "2025-01-02"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddEntry("missing", "2025-01-05"); err == nil {
+		t.Errorf("AddEntry for a missing habit succeeded")
+	}
+
+	if dates, err := store.QueryRange("exercise", "2025-01-02", "2025-01-03"); err != nil || strings.Join(dates, ",") != "2025-01-02,2025-01-03" {
+		t.Errorf("QueryRange = %v, %v", dates, err)
+	}
+	if dates, err := store.QueryRange("reading", "2025-01-01", "2025-01-31"); err != nil || strings.Join(dates, ",") != "2025-01-05" {
+		t.Errorf("QueryRange after AddEntry = %v, %v", dates, err)
+	}
+}
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage backends a Store can be opened with
+const (
+	StorageJSON   = "json"
+	StorageSQLite = "sqlite"
+)
+
+// Store persists habit data. HabitManager loads and saves whole snapshots;
+// the entry operations let callers change a single habit without doing so.
+type Store interface {
+	// Path returns the file the store keeps its data in
+	Path() string
+	// Load reads every activity, creating an empty store if none exists
+	Load() (*ActivitiesData, error)
+	// Save replaces the stored data with data
+	Save(data *ActivitiesData) error
+	// List returns all activity keys in alphabetical order
+	List() ([]string, error)
+	// AddEntry adds one entry for an activity on a YYYY-MM-DD date
+	AddEntry(key, date string) error
+	// RemoveEntry removes one entry for an activity on a date
+	RemoveEntry(key, date string) error
+	// QueryRange returns an activity's entries between start and end
+	// inclusive, in the order they were logged
+	QueryRange(key, start, end string) ([]string, error)
+}
+
+// ConfiguredStorage returns the storage backend to use. HAB_STORAGE
+// overrides the config file, and JSON is the default.
+func ConfiguredStorage() (string, error) {
+	storage := os.Getenv("HAB_STORAGE")
+	if storage == "" {
+		config, err := LoadConfig()
+		if err != nil {
+			return "", err
+		}
+		storage = config.Storage
+	}
+
+	switch storage {
+	case "", StorageJSON:
+		return StorageJSON, nil
+	case StorageSQLite:
+		return StorageSQLite, nil
+	default:
+		return "", fmt.Errorf("unknown storage '%s', use %s or %s", storage, StorageJSON, StorageSQLite)
+	}
+}
+
+// NewStore opens the given storage backend at its default path. The SQLite
+// database sits next to the JSON file with a .db extension.
+func NewStore(storage string) (Store, error) {
+	dataFile := getDefaultDataPath()
+
+	switch storage {
+	case StorageJSON:
+		return NewJSONStore(dataFile), nil
+	case StorageSQLite:
+		return NewSQLiteStore(strings.TrimSuffix(dataFile, filepath.Ext(dataFile)) + ".db"), nil
+	default:
+		return nil, fmt.Errorf("unknown storage '%s', use %s or %s", storage, StorageJSON, StorageSQLite)
+	}
+}
+
+// OpenStore opens the configured storage backend
+func OpenStore() (Store, error) {
+	storage, err := ConfiguredStorage()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(storage)
+}
+
+// defaultStorePath returns the file the configured store keeps its data
+// in, falling back to the JSON file if the config can't be read
+func defaultStorePath() string {
+	store, err := OpenStore()
+	if err != nil {
+		return getDefaultDataPath()
+	}
+	return store.Path()
+}
+
+// Equal reports whether two data sets hold the same activities, entries
+// and goals, treating missing and empty lists alike
+func (d *ActivitiesData) Equal(other *ActivitiesData) bool {
+	if len(d.Activities) != len(other.Activities) || !maps.Equal(d.Goals, other.Goals) {
+		return false
+	}
+
+	for key, activity := range d.Activities {
+		if otherActivity, exists := other.Activities[key]; !exists || !activityEqual(activity, otherActivity) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"math"
+	"time"
+)
+
+// strengthDecay is how much of the previous day's strength carries over to
+// the next, as in Loop Habit Tracker: a daily habit's strength halves in
+// about two weeks without entries, so a single miss dents it rather than
+// resetting it
+var strengthDecay = math.Pow(0.5, 1.0/13)
+
+// Rolling windows for completion rates, in days
+const (
+	ShortWindow = 7
+	LongWindow  = 30
+)
+
+// Trends follows a habit day by day from when it was first tracked up to
+// today. All values are from 0 to 1.
+type Trends struct {
+	Dates     []string
+	Values    []float64 // share of each day's target met, or 1 for a clean day of a habit to avoid
+	Strength  []float64 // exponential moving average of the values
+	Rolling7  []float64 // mean value over the last 7 days
+	Rolling30 []float64 // mean value over the last 30 days
+}
+
+// Len returns how many days the trends cover
+func (t Trends) Len() int {
+	return len(t.Dates)
+}
+
+// DayValue returns how much of a day's target a habit met with count
+// entries, from 0 to 1. A habit to avoid scores 1 on clean days.
+func (t TrackedDays) DayValue(date string, count int) float64 {
+	if t.Activity.IsAvoid() {
+		if t.IsCleanDay(date, count) {
+			return 1
+		}
+		return 0
+	}
+	return math.Min(1, float64(count)/float64(max(1, t.Activity.TargetPerDay)))
+}
+
+// ComputeTrends works out a habit's daily values, strength and rolling
+// completion rates from its first tracked day up to today
+func ComputeTrends(activity Activity, today time.Time) Trends {
+	var trends Trends
+	tracked := activity.Track(today)
+	start, err := time.Parse(DateFormat, tracked.Start)
+	if err != nil {
+		return trends
+	}
+	end, _ := time.Parse(DateFormat, today.Format(DateFormat))
+
+	counts := NewDateCounts(activity.Dates)
+	strength, sum7, sum30 := 0.0, 0.0, 0.0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DateFormat)
+		value := tracked.DayValue(date, counts[date])
+		i := len(trends.Values)
+
+		trends.Dates = append(trends.Dates, date)
+		trends.Values = append(trends.Values, value)
+
+		strength = strength*strengthDecay + value*(1-strengthDecay)
+		trends.Strength = append(trends.Strength, strength)
+
+		// Slide the windows, which are shorter near the first day
+		sum7 += value
+		sum30 += value
+		if i >= ShortWindow {
+			sum7 -= trends.Values[i-ShortWindow]
+		}
+		if i >= LongWindow {
+			sum30 -= trends.Values[i-LongWindow]
+		}
+		trends.Rolling7 = append(trends.Rolling7, sum7/float64(min(i+1, ShortWindow)))
+		trends.Rolling30 = append(trends.Rolling30, sum30/float64(min(i+1, LongWindow)))
+	}
+	return trends
+}
+
+// Trends works out an activity's strength and rolling completion rates up
+// to today
+func (hm *HabitManager) Trends(key string, today time.Time) Trends {
+	return ComputeTrends(hm.data.Activities[key], today)
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var strengthToday = time.Date(2025, 3, 31, 20, 0, 0, 0, time.UTC)
+
+// daysBefore returns the dates from n-1 days before today up to today
+func daysBefore(today time.Time, n int) []string {
+	dates := make([]string, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		dates = append(dates, today.AddDate(0, 0, -i).Format(DateFormat))
+	}
+	return dates
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStrengthConvergesWithDailyEntries(t *testing.T) {
+	dates := daysBefore(strengthToday, 90)
+	trends := ComputeTrends(Activity{Dates: dates}, strengthToday)
+	if trends.Len() != 90 || trends.Dates[0] != dates[0] || trends.Dates[89] != dates[89] {
+		t.Fatalf("trends cover %d days from %s, want 90 from %s", trends.Len(), trends.Dates[0], dates[0])
+	}
+
+	for i, strength := range trends.Strength {
+		if want := 1 - math.Pow(strengthDecay, float64(i+1)); !closeTo(strength, want) {
+			t.Fatalf("strength on day %d = %v, want %v", i+1, strength, want)
+		}
+		if i > 0 && strength <= trends.Strength[i-1] {
+			t.Fatalf("strength fell on day %d with an entry", i+1)
+		}
+	}
+	if last := trends.Strength[89]; last < 0.99 {
+		t.Errorf("strength after 90 days of entries = %v, want close to 1", last)
+	}
+}
+
+func TestStrengthHalfLife(t *testing.T) {
+	// 60 days of entries, then 13 days without
+	dates := daysBefore(strengthToday.AddDate(0, 0, -13), 60)
+	trends := ComputeTrends(Activity{Dates: dates}, strengthToday)
+	peak := trends.Strength[59]
+
+	if oneMiss := trends.Strength[60]; !closeTo(oneMiss, peak*strengthDecay) || oneMiss < 0.9*peak {
+		t.Errorf("strength after one miss = %v from %v, want a small dent", oneMiss, peak)
+	}
+	if halved := trends.Strength[72]; !closeTo(halved, peak/2) {
+		t.Errorf("strength after 13 misses = %v, want half of %v", halved, peak)
+	}
+}
+
+func TestRollingWindows(t *testing.T) {
+	// Ten days from the 22nd, with entries on the 22nd, 24th and 25th
+	activity := Activity{
+		Created: "2025-03-22",
+		Dates:   []string{"2025-03-22", "2025-03-24", "2025-03-25"},
+	}
+	trends := ComputeTrends(activity, strengthToday)
+	if trends.Len() != 10 {
+		t.Fatalf("trends cover %d days, want 10", trends.Len())
+	}
+
+	// The windows only cover the days tracked so far
+	for i, want := range []float64{1, 1.0 / 2, 2.0 / 3, 3.0 / 4, 3.0 / 5, 3.0 / 6, 3.0 / 7} {
+		if !closeTo(trends.Rolling7[i], want) || !closeTo(trends.Rolling30[i], want) {
+			t.Errorf("day %d: rolling rates = %v and %v, want %v", i+1, trends.Rolling7[i], trends.Rolling30[i], want)
+		}
+	}
+
+	// Then the 7-day window slides past the first entries
+	for i, want := range map[int]float64{7: 2.0 / 7, 8: 2.0 / 7, 9: 1.0 / 7} {
+		if !closeTo(trends.Rolling7[i], want) {
+			t.Errorf("day %d: 7-day rate = %v, want %v", i+1, trends.Rolling7[i], want)
+		}
+	}
+	if !closeTo(trends.Rolling30[9], 3.0/10) {
+		t.Errorf("30-day rate on day 10 = %v, want 0.3", trends.Rolling30[9])
+	}
+}
+
+func TestTrendValues(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		activity Activity
+		want     []float64
+	}{
+		{
+			name: "target above 1 scores partial days",
+			activity: Activity{TargetPerDay: 2, Created: "2025-03-29",
+				Dates: []string{"2025-03-29", "2025-03-30", "2025-03-30", "2025-03-30"}},
+			want: []float64{0.5, 1, 0},
+		},
+		{
+			name: "habit to avoid scores clean days",
+			activity: Activity{Polarity: PolarityAvoid, Created: "2025-03-28",
+				Dates: []string{"2025-03-29", "2025-03-29", "2025-03-31"}},
+			want: []float64{1, 0, 1, 0},
+		},
+	} {
+		trends := ComputeTrends(test.activity, strengthToday)
+		if trends.Len() != len(test.want) {
+			t.Errorf("%s: %d days, want %d", test.name, trends.Len(), len(test.want))
+			continue
+		}
+		for i, want := range test.want {
+			if trends.Values[i] != want {
+				t.Errorf("%s: day %d value = %v, want %v", test.name, i+1, trends.Values[i], want)
+			}
+		}
+	}
+
+	if trends := ComputeTrends(Activity{}, strengthToday); trends.Len() != 0 {
+		t.Errorf("untracked habit has %d days of trends", trends.Len())
+	}
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PromptSummary is today's progress across all habits, compact enough to
+// show in a shell prompt
+type PromptSummary struct {
+	Done          int `json:"done"`
+	Total         int `json:"total"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+// Remaining returns how many habits still need entries today
+func (ps PromptSummary) Remaining() int {
+	return ps.Total - ps.Done
+}
+
+// promptCache is the on-disk cache of a PromptSummary, valid while the data
+// file and the day are unchanged
+type promptCache struct {
+	DataFile    string        `json:"data_file"`
+	DataModTime time.Time     `json:"data_mod_time"`
+	DataSize    int64         `json:"data_size"`
+	Date        string        `json:"date"`
+	Summary     PromptSummary `json:"summary"`
+}
+
+// promptCachePath returns where the prompt summary is cached for a data file
+func promptCachePath(dataFile string) string {
+	return filepath.Join(filepath.Dir(dataFile), ".prompt-cache.json")
+}
+
+// LoadPromptSummary returns today's summary, reading it from the cache when
+// the data file hasn't changed since it was computed
+func LoadPromptSummary() (PromptSummary, error) {
+	dataFile := defaultStorePath()
+	today := time.Now().Format("2006-01-02")
+
+	info, err := os.Stat(dataFile)
+	if os.IsNotExist(err) {
+		return PromptSummary{}, nil
+	}
+	if err != nil {
+		return PromptSummary{}, fmt.Errorf("failed to stat data file: %w", err)
+	}
+
+	cachePath := promptCachePath(dataFile)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cache promptCache
+		if json.Unmarshal(data, &cache) == nil &&
+			cache.DataFile == dataFile &&
+			cache.Date == today &&
+			cache.DataSize == info.Size() &&
+			cache.DataModTime.Equal(info.ModTime()) {
+			return cache.Summary, nil
+		}
+	}
+
+	hm := NewHabitManager()
+	if err := hm.Load(); err != nil {
+		return PromptSummary{}, err
+	}
+	summary := hm.PromptSummary(today)
+
+	// A failed cache write only costs speed on the next prompt
+	cache := promptCache{
+		DataFile:    dataFile,
+		DataModTime: info.ModTime(),
+		DataSize:    info.Size(),
+		Date:        today,
+		Summary:     summary,
+	}
+	if data, err := json.Marshal(cache); err == nil {
+		os.WriteFile(cachePath, data, 0644)
+	}
+
+	return summary, nil
+}
+
+// PromptSummary computes how many habits met their target on date and the
+// longest current streak
+func (hm *HabitManager) PromptSummary(date string) PromptSummary {
+	var summary PromptSummary
+	for key, activity := range hm.data.Activities {
+		summary.Total++
+
+		if activity.Track(time.Now()).MetOn(date, hm.CountOn(key, date)) {
+			summary.Done++
+		}
+
+		if streak := hm.calculateStreak(key); streak > summary.LongestStreak {
+			summary.LongestStreak = streak
+		}
+	}
+	return summary
+}
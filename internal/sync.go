@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Branch and file the sync repository keeps habits in
+const (
+	syncBranch = "main"
+	syncFile   = "activities.json"
+)
+
+// SyncResult describes what a sync did
+type SyncResult struct {
+	Committed bool // local changes were committed
+	Pulled    bool // remote changes were fast-forwarded in
+	Merged    bool // diverged histories were merged semantically
+	Pushed    bool // the remote was updated
+	Updated   bool // local habits changed as a result
+
+	Conflicts []MergeConflict // differences the merge had to settle
+}
+
+// Syncer shares habit data between machines through a git repository.
+// The data is exported to a JSON file in a local clone whatever the
+// storage backend, and concurrent changes are merged at the data level
+// so git never writes conflict markers into it.
+type Syncer struct {
+	dir string
+}
+
+// NewSyncer creates a syncer for the default sync directory, which sits
+// in the data directory
+func NewSyncer() *Syncer {
+	return &Syncer{dir: filepath.Join(filepath.Dir(getDefaultDataPath()), "sync")}
+}
+
+// Dir returns the local sync repository path
+func (s *Syncer) Dir() string {
+	return s.dir
+}
+
+// IsInitialized reports whether the sync repository has been set up
+func (s *Syncer) IsInitialized() bool {
+	_, err := os.Stat(filepath.Join(s.dir, ".git"))
+	return err == nil
+}
+
+// Remote returns the URL or path of the remote repository
+func (s *Syncer) Remote() (string, error) {
+	return s.git("remote", "get-url", "origin")
+}
+
+// Init creates the local sync repository pointing at remote. A remote
+// path that doesn't exist yet is created as a bare repository. If the
+// remote already holds habits they are combined with the local ones.
+func (s *Syncer) Init(hm *HabitManager, remote string) (SyncResult, error) {
+	if s.IsInitialized() {
+		return SyncResult{}, fmt.Errorf("sync is already set up in %s", s.dir)
+	}
+
+	if isLocalPath(remote) {
+		absRemote, err := filepath.Abs(remote)
+		if err != nil {
+			return SyncResult{}, fmt.Errorf("failed to resolve '%s': %w", remote, err)
+		}
+		remote = absRemote
+
+		if _, err := os.Stat(remote); os.IsNotExist(err) {
+			if _, err := runGit("", "init", "--bare", remote); err != nil {
+				return SyncResult{}, err
+			}
+			if _, err := runGit(remote, "symbolic-ref", "HEAD", "refs/heads/"+syncBranch); err != nil {
+				return SyncResult{}, err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return SyncResult{}, fmt.Errorf("failed to create sync directory: %w", err)
+	}
+	steps := [][]string{
+		{"init"},
+		{"symbolic-ref", "HEAD", "refs/heads/" + syncBranch},
+		{"remote", "add", "origin", remote},
+	}
+	for _, args := range steps {
+		if _, err := s.git(args...); err != nil {
+			os.RemoveAll(s.dir)
+			return SyncResult{}, err
+		}
+	}
+	if err := s.ensureIdentity(); err != nil {
+		os.RemoveAll(s.dir)
+		return SyncResult{}, err
+	}
+
+	// Start from the remote's history when it has one, so the first sync
+	// adds to it instead of diverging from it
+	if _, err := s.git("fetch", "origin"); err != nil {
+		os.RemoveAll(s.dir)
+		return SyncResult{}, err
+	}
+	if s.hasRemoteBranch() {
+		if _, err := s.git("reset", "--hard", "origin/"+syncBranch); err != nil {
+			os.RemoveAll(s.dir)
+			return SyncResult{}, err
+		}
+		remoteData, err := NewJSONStore(s.filePath()).Load()
+		if err != nil {
+			os.RemoveAll(s.dir)
+			return SyncResult{}, err
+		}
+		if err := hm.Replace(MergeUnion(hm.Data(), remoteData)); err != nil {
+			os.RemoveAll(s.dir)
+			return SyncResult{}, err
+		}
+	}
+
+	return s.Sync(hm)
+}
+
+// Sync commits local changes, merges in the remote's and pushes the result,
+// then loads anything new into hm
+func (s *Syncer) Sync(hm *HabitManager) (SyncResult, error) {
+	var result SyncResult
+	if !s.IsInitialized() {
+		return result, errors.New("sync is not set up, run 'hab sync init <remote>' first")
+	}
+
+	// Export in the same form as the data file, rewriting it only when the
+	// habits changed since encrypting gives different bytes every time
+	local := NewJSONStore(s.filePath())
+	exported, err := local.Load()
+	if err != nil {
+		return result, err
+	}
+	encrypt := IsEncryptedFile(hm.DataFile())
+	if !exported.Equal(hm.Data()) || IsEncryptedFile(local.Path()) != encrypt {
+		local.SetEncrypted(encrypt)
+		if err := local.Save(hm.Data()); err != nil {
+			return result, err
+		}
+	}
+
+	committed, err := s.commit(fmt.Sprintf("Sync from %s", hostname()))
+	if err != nil {
+		return result, err
+	}
+	result.Committed = committed
+
+	if _, err := s.git("fetch", "origin"); err != nil {
+		return result, err
+	}
+
+	remoteRef := "origin/" + syncBranch
+	if s.hasRemoteBranch() {
+		switch {
+		case s.isAncestor(remoteRef, "HEAD"):
+			// Nothing new on the remote
+		case s.isAncestor("HEAD", remoteRef):
+			if _, err := s.git("merge", "--ff-only", remoteRef); err != nil {
+				return result, err
+			}
+			result.Pulled = true
+		default:
+			conflicts, err := s.merge(hm.Data(), remoteRef)
+			if err != nil {
+				return result, err
+			}
+			result.Merged = true
+			result.Conflicts = conflicts
+		}
+	}
+
+	if !s.hasRemoteBranch() || !s.isAncestor("HEAD", remoteRef) {
+		if _, err := s.git("push", "origin", "HEAD:refs/heads/"+syncBranch); err != nil {
+			return result, err
+		}
+		result.Pushed = true
+	}
+
+	synced, err := local.Load()
+	if err != nil {
+		return result, err
+	}
+	if !synced.Equal(hm.Data()) {
+		if err := hm.Replace(synced); err != nil {
+			return result, err
+		}
+		result.Updated = true
+	}
+
+	return result, nil
+}
+
+// merge records a merge commit with remoteRef whose content is a
+// three-way merge of the habits on both sides. The "ours" strategy keeps
+// git from merging the JSON text.
+func (s *Syncer) merge(ours *ActivitiesData, remoteRef string) ([]MergeConflict, error) {
+	theirs, err := s.dataAt(remoteRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *ActivitiesData
+	var conflicts []MergeConflict
+	if baseCommit, err := s.git("merge-base", "HEAD", remoteRef); err == nil {
+		base, err := s.dataAt(baseCommit)
+		if err != nil {
+			return nil, err
+		}
+		merged, conflicts = Merge(base, ours, theirs)
+	} else {
+		// Histories started separately, so there's nothing to compare with
+		merged = MergeUnion(ours, theirs)
+	}
+
+	if _, err := s.git("merge", "--no-commit", "--no-ff", "--allow-unrelated-histories", "-s", "ours", remoteRef); err != nil {
+		return nil, err
+	}
+	if err := NewJSONStore(s.filePath()).Save(merged); err != nil {
+		return nil, err
+	}
+	if _, err := s.git("add", syncFile); err != nil {
+		return nil, err
+	}
+	if _, err := s.git("commit", "--no-edit", "-m", fmt.Sprintf("Merge habits from %s into %s", remoteRef, hostname())); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// dataAt reads the habits committed at a revision
+func (s *Syncer) dataAt(revision string) (*ActivitiesData, error) {
+	contents, err := s.git("show", revision+":"+syncFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ParseActivitiesData([]byte(contents))
+	if err != nil {
+		return nil, fmt.Errorf("%s at %s: %w", syncFile, revision, err)
+	}
+	return data, nil
+}
+
+// commit commits the data file if it changed, reporting whether it did
+func (s *Syncer) commit(message string) (bool, error) {
+	if _, err := s.git("add", syncFile); err != nil {
+		return false, err
+	}
+	if status, err := s.git("status", "--porcelain", "--", syncFile); err != nil || status == "" {
+		return false, err
+	}
+	if _, err := s.git("commit", "-m", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureIdentity sets a repository-local committer when git has none, so
+// syncing works on machines that never configured git
+func (s *Syncer) ensureIdentity() error {
+	if email, _ := s.git("config", "user.email"); email != "" {
+		return nil
+	}
+	if _, err := s.git("config", "user.name", "hab"); err != nil {
+		return err
+	}
+	_, err := s.git("config", "user.email", "hab@"+hostname())
+	return err
+}
+
+// hasRemoteBranch reports whether the fetched remote has the sync branch
+func (s *Syncer) hasRemoteBranch() bool {
+	_, err := s.git("rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+syncBranch)
+	return err == nil
+}
+
+// isAncestor reports whether commit a is an ancestor of commit b
+func (s *Syncer) isAncestor(a, b string) bool {
+	_, err := s.git("merge-base", "--is-ancestor", a, b)
+	return err == nil
+}
+
+// filePath returns the path of the data file in the sync repository
+func (s *Syncer) filePath() string {
+	return filepath.Join(s.dir, syncFile)
+}
+
+// git runs a git command in the sync repository
+func (s *Syncer) git(args ...string) (string, error) {
+	return runGit(s.dir, args...)
+}
+
+// runGit runs git in dir and returns its trimmed output. Failures include
+// git's own message.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", errors.New("git is not installed")
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// isLocalPath reports whether a remote is a filesystem path rather than a
+// URL or scp-style address
+func isLocalPath(remote string) bool {
+	if strings.Contains(remote, "://") {
+		return false
+	}
+	host, _, found := strings.Cut(remote, ":")
+	return !found || strings.Contains(host, "/") || filepath.VolumeName(remote) != ""
+}
+
+// hostname names this machine in commit messages
+func hostname() string {
+	if name, err := os.Hostname(); err == nil {
+		return name
+	}
+	return "unknown host"
+}
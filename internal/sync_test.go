@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// isolateGit keeps the user's git config out of the test, so the sync
+// repository has to set its own identity
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+// newSyncMachine gives a machine its own data file and sync directory,
+// holding one habit with an entry
+func newSyncMachine(t *testing.T, key, date string) (*HabitManager, *Syncer) {
+	t.Helper()
+	dir := t.TempDir()
+	hm := NewHabitManagerWithStore(NewJSONStore(filepath.Join(dir, "activities.json")))
+	err := hm.Mutate(func(tx *Tx) error {
+		if err := tx.CreateActivity(key, key, "green", 1); err != nil {
+			return err
+		}
+		return tx.AddEntry(key, date)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hm, &Syncer{dir: filepath.Join(dir, "sync")}
+}
+
+func TestSyncBetweenMachines(t *testing.T) {
+	isolateGit(t)
+	remote := filepath.Join(t.TempDir(), "habits.git")
+
+	laptop, laptopSync := newSyncMachine(t, "exercise", "2025-01-01")
+	result, err := laptopSync.Init(laptop, remote)
+	if err != nil {
+		t.Fatalf("Init on the laptop: %v", err)
+	}
+	if !result.Committed || !result.Pushed {
+		t.Errorf("first Init = %+v, want a commit pushed to the new remote", result)
+	}
+
+	// A second machine joining combines its habits with the remote's
+	phone, phoneSync := newSyncMachine(t, "reading", "2025-01-02")
+	if _, err := phoneSync.Init(phone, remote); err != nil {
+		t.Fatalf("Init on the phone: %v", err)
+	}
+	if _, ok := phone.GetActivity("exercise"); !ok {
+		t.Errorf("phone didn't get the laptop's habit")
+	}
+
+	// Both log entries before syncing, so their histories diverge
+	if err := laptop.AddEntry("exercise", "2025-01-03"); err != nil {
+		t.Fatal(err)
+	}
+	if err := phone.AddEntry("reading", "2025-01-03"); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := phoneSync.Sync(phone); err != nil || !result.Pushed || result.Merged {
+		t.Fatalf("phone Sync = %+v, %v, want a push", result, err)
+	}
+	if result, err := laptopSync.Sync(laptop); err != nil || !result.Merged || !result.Updated || len(result.Conflicts) != 0 {
+		t.Fatalf("laptop Sync = %+v, %v, want a clean merge", result, err)
+	}
+	if result, err := phoneSync.Sync(phone); err != nil || !result.Pulled || !result.Updated {
+		t.Fatalf("phone Sync after the merge = %+v, %v, want a pull", result, err)
+	}
+
+	if !laptop.Data().Equal(phone.Data()) {
+		t.Errorf("machines differ after syncing:\n%+v\n%+v", laptop.Data(), phone.Data())
+	}
+	if laptop.CountOn("exercise", "2025-01-03") != 1 || laptop.CountOn("reading", "2025-01-03") != 1 {
+		t.Errorf("synced habits are missing entries: %+v", laptop.Data())
+	}
+
+	// The synced data also reached the laptop's data file
+	saved, err := NewJSONStore(laptop.DataFile()).Load()
+	if err != nil || !saved.Equal(laptop.Data()) {
+		t.Errorf("laptop data file = %+v, %v, want the synced habits", saved, err)
+	}
+}
+
+func TestSyncInitCleansUpAfterUnreadableRemote(t *testing.T) {
+	isolateGit(t)
+	remote := filepath.Join(t.TempDir(), "habits.git")
+	if _, err := runGit("", "init", "--bare", remote); err != nil {
+		t.Fatal(err)
+	}
+
+	// Push a data file hab can't read
+	scratch := t.TempDir()
+	if err := os.WriteFile(filepath.Join(scratch, syncFile), []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"add", syncFile},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "broken"},
+		{"push", remote, "HEAD:refs/heads/" + syncBranch},
+	} {
+		if _, err := runGit(scratch, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hm, syncer := newSyncMachine(t, "exercise", "2025-01-01")
+	before := hm.Data().clone()
+	if _, err := syncer.Init(hm, remote); err == nil {
+		t.Fatal("Init succeeded with an unreadable remote")
+	}
+	if syncer.IsInitialized() {
+		t.Errorf("failed Init left a sync repository behind")
+	}
+	if _, err := os.Stat(syncer.Dir()); !os.IsNotExist(err) {
+		t.Errorf("failed Init left %s behind: %v", syncer.Dir(), err)
+	}
+	if !hm.Data().Equal(before) {
+		t.Errorf("failed Init changed local habits")
+	}
+}
+
+func TestSyncNotInitialized(t *testing.T) {
+	hm, syncer := newSyncMachine(t, "exercise", "2025-01-01")
+	if _, err := syncer.Sync(hm); err == nil {
+		t.Errorf("Sync without Init succeeded")
+	}
+}
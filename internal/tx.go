@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Tx is a batch of changes made to a working copy of the habit data. Mutate
+// saves the copy once when the batch succeeds and discards it otherwise.
+type Tx struct {
+	data    *ActivitiesData
+	touched map[string]bool // activities whose entries or existence changed
+}
+
+// Mutate runs fn against a working copy of the data and saves all of its
+// changes with a single write. If fn or the save fails, nothing changes.
+func (hm *HabitManager) Mutate(fn func(tx *Tx) error) error {
+	tx := &Tx{data: hm.data.clone(), touched: make(map[string]bool)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := hm.backup(); err != nil {
+		return fmt.Errorf("failed to back up data before saving: %w", err)
+	}
+
+	previous := hm.data
+	hm.data = tx.data
+	if err := hm.Save(); err != nil {
+		hm.data = previous
+		return err
+	}
+
+	// Keep the date index in step with the committed entries
+	for key := range tx.touched {
+		hm.reindex(key)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the data
+func (d *ActivitiesData) clone() *ActivitiesData {
+	copied := &ActivitiesData{Version: d.Version, Activities: make(map[string]Activity, len(d.Activities))}
+	for key, activity := range d.Activities {
+		activity.Dates = append(make([]string, 0, len(activity.Dates)), activity.Dates...)
+		activity.Reminders = append([]string(nil), activity.Reminders...)
+		activity.Authors = cloneAuthors(activity.Authors)
+		copied.Activities[key] = activity
+	}
+	copied.Goals = maps.Clone(d.Goals)
+	return copied
+}
+
+// GetActivity returns a specific activity by key, including pending changes
+func (tx *Tx) GetActivity(key string) (Activity, bool) {
+	activity, exists := tx.data.Activities[key]
+	return activity, exists
+}
+
+// CreateActivity creates a new activity
+func (tx *Tx) CreateActivity(key, name, color string, targetPerDay int) error {
+	if _, exists := tx.data.Activities[key]; exists {
+		return fmt.Errorf("activity '%s' already exists", key)
+	}
+
+	if targetPerDay <= 0 {
+		targetPerDay = 1
+	}
+
+	tx.data.Activities[key] = Activity{
+		Name:         name,
+		Color:        color,
+		Dates:        []string{},
+		TargetPerDay: targetPerDay,
+		Created:      time.Now().Format(DateFormat),
+	}
+	tx.touched[key] = true
+	return nil
+}
+
+// AddEntry adds an unattributed date entry to an activity
+func (tx *Tx) AddEntry(key, dateStr string) error {
+	return tx.AddEntryBy(key, dateStr, "")
+}
+
+// AddEntryBy adds a date entry to an activity, logged by author unless it's
+// empty
+func (tx *Tx) AddEntryBy(key, dateStr, author string) error {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	// Validate date format
+	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
+		return fmt.Errorf("invalid date format '%s', use YYYY-MM-DD", dateStr)
+	}
+
+	// Add the date
+	activity.Dates = append(activity.Dates, dateStr)
+	if author != "" {
+		if activity.Authors == nil {
+			activity.Authors = make(map[string][]string)
+		}
+		activity.Authors[dateStr] = append(activity.Authors[dateStr], author)
+	}
+	tx.data.Activities[key] = activity
+	tx.touched[key] = true
+	return nil
+}
+
+// RemoveEntry removes a date entry from an activity, preferring an
+// unattributed one
+func (tx *Tx) RemoveEntry(key, dateStr string) error {
+	return tx.RemoveEntryBy(key, dateStr, "")
+}
+
+// RemoveEntryBy removes a date entry logged by author from an activity, or
+// any entry on the date when author is empty
+func (tx *Tx) RemoveEntryBy(key, dateStr, author string) error {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	i := slices.Index(activity.Dates, dateStr)
+	if i < 0 {
+		return fmt.Errorf("date '%s' not found in activity '%s'", dateStr, key)
+	}
+
+	if author != "" {
+		j := slices.Index(activity.Authors[dateStr], author)
+		if j < 0 {
+			return fmt.Errorf("no entry by %s on '%s' in activity '%s'", author, dateStr, key)
+		}
+		activity.Authors[dateStr] = slices.Delete(activity.Authors[dateStr], j, j+1)
+	}
+
+	// Remove the first occurrence of the date
+	activity.Dates = slices.Delete(activity.Dates, i, i+1)
+	activity.trimAuthors(dateStr)
+	tx.data.Activities[key] = activity
+	tx.touched[key] = true
+	return nil
+}
+
+// DeleteActivity removes an activity entirely
+func (tx *Tx) DeleteActivity(key string) error {
+	if _, exists := tx.data.Activities[key]; !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	delete(tx.data.Activities, key)
+	tx.touched[key] = true
+
+	// Its goals go with it
+	for id, goal := range tx.data.Goals {
+		if goal.Habit == key {
+			delete(tx.data.Goals, id)
+		}
+	}
+	return nil
+}
+
+// UpdateActivity updates activity metadata
+func (tx *Tx) UpdateActivity(key string, name, color string, targetPerDay int) error {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	if name != "" {
+		activity.Name = name
+	}
+	if color != "" {
+		activity.Color = color
+	}
+	if targetPerDay > 0 {
+		activity.TargetPerDay = targetPerDay
+	}
+
+	tx.data.Activities[key] = activity
+	return nil
+}
+
+// SetReminders replaces the daily reminder times (HH:MM) of an activity
+func (tx *Tx) SetReminders(key string, times []string) error {
+	activity, exists := tx.data.Activities[key]
+	if !exists {
+		return fmt.Errorf("activity '%s' does not exist", key)
+	}
+
+	for _, t := range times {
+		if _, err := time.Parse("15:04", t); err != nil {
+			return fmt.Errorf("invalid reminder time '%s', use HH:MM", t)
+		}
+	}
+
+	sorted := append([]string(nil), times...)
+	sort.Strings(sorted)
+	activity.Reminders = sorted
+	tx.data.Activities[key] = activity
+	return nil
+}
+
+// Replace swaps the working copy for data, such as a merged copy from
+// another machine
+func (tx *Tx) Replace(data *ActivitiesData) {
+	for key := range tx.data.Activities {
+		tx.touched[key] = true
+	}
+	tx.data = data.clone()
+	for key := range tx.data.Activities {
+		tx.touched[key] = true
+	}
+}
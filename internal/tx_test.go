@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// failingStore is a JSON store whose saves fail
+type failingStore struct {
+	*JSONStore
+}
+
+func (failingStore) Save(data *ActivitiesData) error {
+	return errors.New("disk full")
+}
+
+// newTestManager creates a manager over a data file in a temporary
+// directory holding two habits and a goal
+func newTestManager(t *testing.T) *HabitManager {
+	t.Helper()
+	hm := NewHabitManagerWithStore(NewJSONStore(filepath.Join(t.TempDir(), "activities.json")))
+	err := hm.Mutate(func(tx *Tx) error {
+		for _, key := range []string{"exercise", "reading"} {
+			if err := tx.CreateActivity(key, key, "green", 1); err != nil {
+				return err
+			}
+			if err := tx.AddEntryBy(key, "2025-01-01", "alice"); err != nil {
+				return err
+			}
+		}
+		_, err := tx.AddGoal("", Goal{Habit: "exercise", Kind: GoalTotal, Target: 10, Start: "2025-01-01", End: "2025-12-31"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hm
+}
+
+func TestMutateFailureChangesNothing(t *testing.T) {
+	hm := newTestManager(t)
+	before := hm.Data().clone()
+	contents, err := os.ReadFile(hm.DataFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := ListBackups(hm.DataFile())
+
+	failure := errors.New("stop")
+	err = hm.Mutate(func(tx *Tx) error {
+		if err := tx.AddEntryBy("exercise", "2025-01-01", "bob"); err != nil {
+			return err
+		}
+		if err := tx.RemoveEntryBy("reading", "2025-01-01", "alice"); err != nil {
+			return err
+		}
+		if err := tx.UpdateActivity("exercise", "Workout", "red", 3); err != nil {
+			return err
+		}
+		if err := tx.DeleteGoal("exercise-1"); err != nil {
+			return err
+		}
+		if err := tx.CreateActivity("new", "New", "blue", 1); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Mutate = %v, want %v", err, failure)
+	}
+
+	if !hm.Data().Equal(before) {
+		t.Errorf("data after a failed batch = %+v, want %+v", hm.Data(), before)
+	}
+	if hm.CountOn("exercise", "2025-01-01") != 1 || hm.CountOn("reading", "2025-01-01") != 1 {
+		t.Errorf("index changed after a failed batch")
+	}
+
+	after, err := os.ReadFile(hm.DataFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(after, contents) {
+		t.Errorf("data file changed after a failed batch:\n%s", after)
+	}
+	if backupsAfter, _ := ListBackups(hm.DataFile()); len(backupsAfter) != len(backups) {
+		t.Errorf("failed batch made %d backups", len(backupsAfter)-len(backups))
+	}
+}
+
+func TestMutateSaveFailureChangesNothing(t *testing.T) {
+	hm := newTestManager(t)
+	hm.store = failingStore{hm.store.(*JSONStore)}
+	before := hm.Data().clone()
+
+	err := hm.Mutate(func(tx *Tx) error {
+		return tx.AddEntry("exercise", "2025-01-02")
+	})
+	if err == nil {
+		t.Fatal("Mutate succeeded with a failing store")
+	}
+	if !hm.Data().Equal(before) || hm.CountOn("exercise", "2025-01-02") != 0 {
+		t.Errorf("data changed after a failed save")
+	}
+}
+
+func TestMutateReindexesTouchedKeys(t *testing.T) {
+	hm := newTestManager(t)
+	reading := reflect.ValueOf(hm.DateCounts("reading")).Pointer()
+
+	err := hm.Mutate(func(tx *Tx) error {
+		if err := tx.AddEntry("exercise", "2025-01-02"); err != nil {
+			return err
+		}
+		return tx.UpdateActivity("reading", "Books", "", 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hm.CountOn("exercise", "2025-01-02") != 1 {
+		t.Errorf("new entry missing from the index")
+	}
+	if reflect.ValueOf(hm.DateCounts("reading")).Pointer() != reading {
+		t.Errorf("index of a habit whose entries didn't change was rebuilt")
+	}
+
+	if err := hm.Mutate(func(tx *Tx) error { return tx.DeleteActivity("exercise") }); err != nil {
+		t.Fatal(err)
+	}
+	if hm.DateCounts("exercise") != nil {
+		t.Errorf("deleted habit is still indexed")
+	}
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"time"
+
+	"hab/internal"
+)
+
+// PNG heatmap geometry, in pixels
+const (
+	pngCellSize = 10
+	pngCellGap  = 3
+	pngPadding  = 6
+)
+
+// activityGrid lays out a single activity's timeline in weeks
+func activityGrid(activity internal.Activity, timeline TimelineDays) [][]ContributionGrid {
+	return generateGrid(map[string]internal.Activity{"": activity}, timeline)
+}
+
+// WriteHeatmapSVG writes an activity's contribution grid as an SVG image
+func WriteHeatmapSVG(w io.Writer, activity internal.Activity, timeline TimelineDays) error {
+	_, err := io.WriteString(w, renderHeatmapSVG(activity, activityGrid(activity, timeline)))
+	return err
+}
+
+// WriteHeatmapPNG writes an activity's contribution grid as a PNG image.
+// It only uses the standard library so it works headless.
+func WriteHeatmapPNG(w io.Writer, activity internal.Activity, timeline TimelineDays) error {
+	grid := activityGrid(activity, timeline)
+	today := time.Now()
+
+	width := 2*pngPadding + len(grid)*(pngCellSize+pngCellGap) - pngCellGap
+	height := 2*pngPadding + 7*(pngCellSize+pngCellGap) - pngCellGap
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	counts := internal.NewDateCounts(activity.Dates)
+	tracked := activity.Track(today)
+	base := parseHex(getColorHex(activity.Color))
+	empty := parseHex(svgEmptyFill)
+
+	for week, days := range grid {
+		for row, cell := range days {
+			if cell.Date.After(today) {
+				continue
+			}
+
+			fill := empty
+			date := cell.Date.Format("2006-01-02")
+			if level := dayLevel(tracked, counts[date], date); level != LevelNone {
+				opacity, _ := strconv.ParseFloat(levelOpacity[level], 64)
+				fill = blendOverWhite(base, opacity)
+			}
+
+			x := pngPadding + week*(pngCellSize+pngCellGap)
+			y := pngPadding + row*(pngCellSize+pngCellGap)
+			rect := image.Rect(x, y, x+pngCellSize, y+pngCellSize)
+			draw.Draw(img, rect, image.NewUniform(fill), image.Point{}, draw.Src)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// parseHex converts a #rrggbb string to a color
+func parseHex(hex string) color.RGBA {
+	var r, g, b uint8
+	fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+// blendOverWhite flattens a translucent color onto a white background
+func blendOverWhite(c color.RGBA, opacity float64) color.RGBA {
+	blend := func(v uint8) uint8 {
+		return uint8(float64(v)*opacity + 255*(1-opacity) + 0.5)
+	}
+	return color.RGBA{R: blend(c.R), G: blend(c.G), B: blend(c.B), A: 0xff}
+}
+
+// RenderBadgeSVG renders a shields-style flat badge such as "streak | 42d"
+func RenderBadgeSVG(label, value, colorName string) string {
+	label, value = html.EscapeString(label), html.EscapeString(value)
+
+	// Approximate Verdana 11px glyph widths, as shields.io does
+	labelWidth := badgeTextWidth(html.UnescapeString(label))
+	valueWidth := badgeTextWidth(html.UnescapeString(value))
+	width := labelWidth + valueWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+
+		`<title>%s: %s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+
+		`<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+
+		`</g></svg>`,
+		width, label, value,
+		label, value,
+		width,
+		labelWidth, labelWidth, valueWidth, getColorHex(colorName), width,
+		labelWidth/2, label, labelWidth/2, label,
+		labelWidth+valueWidth/2, value, labelWidth+valueWidth/2, value)
+}
+
+// badgeTextWidth estimates the pixel width of a badge segment with padding
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
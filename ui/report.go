@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"hab/internal"
+)
+
+// SVG heatmap geometry, in pixels
+const (
+	svgCellSize   = 11
+	svgCellGap    = 3
+	svgLeftMargin = 30
+	svgTopMargin  = 16
+)
+
+// Hex colors matching the terminal color names
+var colorHex = map[string]string{
+	"red":     "#d73a49",
+	"green":   "#2da44e",
+	"yellow":  "#d4a72c",
+	"blue":    "#0969da",
+	"magenta": "#bf3989",
+	"cyan":    "#1b9aaa",
+	"gray":    "#8c959f",
+}
+
+// Fill used for days without activity
+const svgEmptyFill = "#ebedf0"
+
+// Opacity of the habit color for each completion level
+var levelOpacity = map[CompletionLevel]string{
+	LevelLow:      "0.35",
+	LevelPartial:  "0.65",
+	LevelComplete: "1",
+}
+
+// getColorHex converts color names to hex codes for SVG output
+func getColorHex(colorName string) string {
+	if hex, exists := colorHex[colorName]; exists {
+		return hex
+	}
+	return colorHex["gray"]
+}
+
+// renderHeatmapSVG draws an activity's contribution grid as an SVG document,
+// using the same week layout as the terminal grid
+func renderHeatmapSVG(activity internal.Activity, grid [][]ContributionGrid) string {
+	today := time.Now()
+	width := svgLeftMargin + len(grid)*(svgCellSize+svgCellGap)
+	height := svgTopMargin + 7*(svgCellSize+svgCellGap)
+	fill := getColorHex(activity.Color)
+	counts := internal.NewDateCounts(activity.Dates)
+	tracked := activity.Track(today)
+
+	var s strings.Builder
+	fmt.Fprintf(&s, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="9" fill="#57606a">`,
+		width, height, width, height)
+
+	// Day labels on alternate rows, as GitHub does
+	for row, label := range []string{"", "Mon", "", "Wed", "", "Fri", ""} {
+		if label == "" {
+			continue
+		}
+		y := svgTopMargin + row*(svgCellSize+svgCellGap) + svgCellSize - 2
+		fmt.Fprintf(&s, `<text x="0" y="%d">%s</text>`, y, label)
+	}
+
+	lastMonth := time.Month(0)
+	for week, days := range grid {
+		x := svgLeftMargin + week*(svgCellSize+svgCellGap)
+
+		// Month label above the first week starting in a new month
+		if month := days[len(days)-1].Date.Month(); month != lastMonth {
+			if lastMonth != 0 || days[0].Date.Day() == 1 {
+				fmt.Fprintf(&s, `<text x="%d" y="10">%s</text>`, x, month.String()[:3])
+			}
+			lastMonth = month
+		}
+
+		for row, cell := range days {
+			if cell.Date.After(today) {
+				continue
+			}
+
+			dateStr := cell.Date.Format("2006-01-02")
+			level := dayLevel(tracked, counts[dateStr], dateStr)
+			y := svgTopMargin + row*(svgCellSize+svgCellGap)
+
+			cellFill, opacity := svgEmptyFill, "1"
+			if level != LevelNone {
+				cellFill, opacity = fill, levelOpacity[level]
+			}
+			fmt.Fprintf(&s, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s" fill-opacity="%s"><title>%s</title></rect>`,
+				x, y, svgCellSize, svgCellSize, cellFill, opacity, dateStr)
+		}
+	}
+
+	s.WriteString(`</svg>`)
+	return s.String()
+}
+
+// monthSummary aggregates one calendar month of a habit
+type monthSummary struct {
+	Month    string
+	CheckIns int
+	DaysMet  int
+	Days     int
+}
+
+// Percent returns the share of days in the month where the target was met
+func (ms monthSummary) Percent() float64 {
+	if ms.Days == 0 {
+		return 0
+	}
+	return float64(ms.DaysMet) / float64(ms.Days) * 100
+}
+
+// summariseMonths totals check-ins and target-met days for each calendar
+// month between start and end, newest first. For a habit to avoid the days
+// met are its clean days.
+func summariseMonths(activity internal.Activity, start, end time.Time) []monthSummary {
+	counts := internal.NewDateCounts(activity.Dates)
+	tracked := activity.Track(end)
+
+	var months []monthSummary
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		label := day.Format("January 2006")
+		if len(months) == 0 || months[len(months)-1].Month != label {
+			months = append(months, monthSummary{Month: label})
+		}
+
+		current := &months[len(months)-1]
+		date := day.Format("2006-01-02")
+		count := counts[date]
+		current.CheckIns += count
+		current.Days++
+		if tracked.MetOn(date, count) {
+			current.DaysMet++
+		}
+	}
+
+	// Newest month first
+	for i, j := 0, len(months)-1; i < j; i, j = i+1, j-1 {
+		months[i], months[j] = months[j], months[i]
+	}
+	return months
+}
+
+// reportHabit is the template data for one habit section
+type reportHabit struct {
+	Key            string
+	Name           string
+	Color          string
+	Heatmap        template.HTML
+	TotalEntries   int
+	UniqueDays     int
+	CurrentStreak  int
+	TargetPerDay   int
+	DaysMet        int
+	TimelineDays   int
+	CompletionRate float64
+	Months         []monthSummary
+}
+
+// reportData is the template data for the whole report
+type reportData struct {
+	Generated string
+	Timeline  string
+	Habits    []reportHabit
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Habit Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292f; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+h1 { margin-bottom: 0; }
+.meta { color: #57606a; margin-top: 0.25em; }
+section { border: 1px solid #d0d7de; border-radius: 6px; padding: 1em 1.25em; margin: 1.5em 0; }
+h2 { margin-top: 0; }
+.heatmap { overflow-x: auto; }
+.stats { display: flex; flex-wrap: wrap; gap: 1.5em; margin: 1em 0; }
+.stat b { display: block; font-size: 1.4em; }
+.stat span { color: #57606a; font-size: 0.85em; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #eaeef2; }
+td.num, th.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Habit Report</h1>
+<p class="meta">Generated {{.Generated}} &middot; last {{.Timeline}}</p>
+{{range .Habits}}
+<section>
+<h2 style="color: {{.Color}}">{{.Name}}</h2>
+<div class="heatmap">{{.Heatmap}}</div>
+<div class="stats">
+<div class="stat"><b>{{.CurrentStreak}}</b><span>day streak</span></div>
+<div class="stat"><b>{{.TotalEntries}}</b><span>total entries</span></div>
+<div class="stat"><b>{{.UniqueDays}}</b><span>unique days</span></div>
+<div class="stat"><b>{{.TargetPerDay}}</b><span>target per day</span></div>
+<div class="stat"><b>{{printf "%.1f" .CompletionRate}}%</b><span>{{.DaysMet}}/{{.TimelineDays}} days target met</span></div>
+</div>
+<table>
+<tr><th>Month</th><th class="num">Check-ins</th><th class="num">Days met</th><th class="num">Completion</th></tr>
+{{range .Months}}<tr><td>{{.Month}}</td><td class="num">{{.CheckIns}}</td><td class="num">{{.DaysMet}}/{{.Days}}</td><td class="num">{{printf "%.0f" .Percent}}%</td></tr>
+{{end}}</table>
+</section>
+{{else}}
+<p>No habits tracked yet.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTMLReport writes a self-contained HTML report with an SVG heatmap,
+// streak and completion stats, and monthly summaries for every habit
+func WriteHTMLReport(w io.Writer, hm *internal.HabitManager, timeline TimelineDays) error {
+	activities := hm.GetActivities()
+	grid := generateGrid(activities, timeline)
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -int(timeline-1))
+
+	data := reportData{
+		Generated: end.Format("January 2, 2006"),
+		Timeline:  timelineLabel(timeline),
+	}
+
+	for _, key := range hm.SortedKeys() {
+		activity := activities[key]
+		stats, err := hm.GetStats(key)
+		if err != nil {
+			return err
+		}
+
+		months := summariseMonths(activity, start, end)
+		daysMet := 0
+		for _, month := range months {
+			daysMet += month.DaysMet
+		}
+
+		data.Habits = append(data.Habits, reportHabit{
+			Key:            key,
+			Name:           activity.Name,
+			Color:          getColorHex(activity.Color),
+			Heatmap:        template.HTML(renderHeatmapSVG(activity, grid)),
+			TotalEntries:   stats["total_entries"].(int),
+			UniqueDays:     stats["unique_days"].(int),
+			CurrentStreak:  stats["current_streak"].(int),
+			TargetPerDay:   max(1, activity.TargetPerDay),
+			DaysMet:        daysMet,
+			TimelineDays:   int(timeline),
+			CompletionRate: float64(daysMet) / float64(timeline) * 100,
+			Months:         months,
+		})
+	}
+
+	return reportTemplate.Execute(w, data)
+}
+
+// timelineLabel describes a timeline for display
+func timelineLabel(timeline TimelineDays) string {
+	switch timeline {
+	case Timeline3Months:
+		return "3 months"
+	case Timeline6Months:
+		return "6 months"
+	default:
+		return "12 months"
+	}
+}
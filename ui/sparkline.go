@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkChars are the bars of a sparkline from lowest to highest
+var sparkChars = map[RenderingLevel][]string{
+	ASCII:         {"_", ".", ",", "-", "~", "=", "+", "#"},
+	ASCIIExtended: {"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"},
+	Unicode:       {"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"},
+}
+
+// sparkline draws the latest width values, each from 0 to 1, as a row of
+// bars
+func sparkline(values []float64, width int, level RenderingLevel) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	bars := sparkChars[level]
+	var s strings.Builder
+	for _, value := range values {
+		i := int(math.Round(math.Max(0, math.Min(1, value)) * float64(len(bars)-1)))
+		s.WriteString(bars[i])
+	}
+	return s.String()
+}
+
+// Sparkline draws the latest width values, each from 0 to 1, with the bars
+// the terminal can show
+func Sparkline(values []float64, width int) string {
+	return sparkline(values, width, detectRenderingLevel())
+}
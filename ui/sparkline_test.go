@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		values []float64
+		width  int
+		level  RenderingLevel
+		want   string
+	}{
+		{"empty", nil, 10, Unicode, ""},
+		{"lowest to highest", []float64{0, 1.0 / 7, 2.0 / 7, 3.0 / 7, 4.0 / 7, 5.0 / 7, 6.0 / 7, 1}, 10, Unicode, "▁▂▃▄▅▆▇█"},
+		{"rounds to the nearest bar", []float64{0.07, 0.08, 0.5}, 10, Unicode, "▁▂▅"},
+		{"clamps out of range values", []float64{-0.5, 1.5}, 10, Unicode, "▁█"},
+		{"keeps the latest values", []float64{0, 0, 1, 1, 1}, 3, Unicode, "███"},
+		{"ASCII", []float64{0, 0.5, 1}, 10, ASCII, "_~#"},
+	} {
+		if got := sparkline(test.values, test.width, test.level); got != test.want {
+			t.Errorf("%s: sparkline = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
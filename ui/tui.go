@@ -3,13 +3,15 @@ package ui
 import (
 	"fmt"
 	"os"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -62,6 +64,7 @@ const (
 	AllActivities ViewMode = iota
 	SingleActivity
 	HabitSelection
+	ProfileSelection
 )
 
 // HabitItem represents an item in the habit list
@@ -73,10 +76,29 @@ type HabitItem struct {
 func (i HabitItem) FilterValue() string { return i.activity.Name }
 func (i HabitItem) Title() string       { return i.activity.Name }
 func (i HabitItem) Description() string {
+	if i.activity.IsAvoid() {
+		return fmt.Sprintf("Key: %s • Color: %s • Avoid • Slips: %d",
+			i.key, i.activity.Color, len(i.activity.Dates))
+	}
 	return fmt.Sprintf("Key: %s • Color: %s • Target: %d/day • Entries: %d", 
 		i.key, i.activity.Color, max(1, i.activity.TargetPerDay), len(i.activity.Dates))
 }
 
+// ProfileItem represents an item in the profile list
+type ProfileItem struct {
+	profile internal.Profile
+	active  bool
+}
+
+func (i ProfileItem) FilterValue() string { return i.profile.Name }
+func (i ProfileItem) Title() string {
+	if i.active {
+		return i.profile.Name + " (active)"
+	}
+	return i.profile.Name
+}
+func (i ProfileItem) Description() string { return i.profile.Dir }
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -94,21 +116,26 @@ type ContributionGrid struct {
 
 // Key bindings
 type keyMap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Left        key.Binding
-	Right       key.Binding
-	Enter       key.Binding
-	Space       key.Binding
-	Tab         key.Binding
-	Quit        key.Binding
-	Escape      key.Binding
-	AllView     key.Binding
-	Timeline3m  key.Binding
-	Timeline6m  key.Binding
-	Timeline12m key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	Enter        key.Binding
+	Space        key.Binding
+	Tab          key.Binding
+	Quit         key.Binding
+	Escape       key.Binding
+	AllView      key.Binding
+	Timeline3m   key.Binding
+	Timeline6m   key.Binding
+	Timeline12m  key.Binding
 	ToggleLegend key.Binding
-	Help        key.Binding
+	Jump         key.Binding
+	Profiles     key.Binding
+	ByPerson     key.Binding
+	Help         key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
@@ -117,8 +144,9 @@ func (k keyMap) ShortHelp() []key.Binding {
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter, k.Space},
-		{k.Tab, k.AllView, k.ToggleLegend},
+		{k.Up, k.Down, k.PageUp, k.PageDown},
+		{k.Enter, k.Space},
+		{k.Tab, k.Jump, k.AllView, k.ToggleLegend, k.ByPerson, k.Profiles},
 		{k.Timeline3m, k.Timeline6m, k.Timeline12m},
 		{k.Help, k.Quit, k.Escape},
 	}
@@ -133,6 +161,14 @@ var keys = keyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "page down"),
+	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "select/log activity"),
@@ -173,12 +209,45 @@ var keys = keyMap{
 		key.WithKeys("l"),
 		key.WithHelp("l", "toggle legend"),
 	),
+	Jump: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "jump to habit"),
+	),
+	Profiles: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "switch profile"),
+	),
+	ByPerson: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "color by person"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
 	),
 }
 
+// Line counts of one rendered habit grid (title plus seven day rows) and of a
+// grid plus the blank lines separating it from the next in the all view
+const (
+	activityGridHeight  = 8
+	activityBlockHeight = 10
+)
+
+// personColors are given to the people who log shared habits, in
+// alphabetical order of their names, when cells are colored by person
+var personColors = []string{"1", "2", "3", "4", "5", "6", "9", "10", "11", "12", "13", "14"}
+
+// quickSelectTimeout is how long the all-activities view waits for another
+// digit before selecting the habit typed so far
+const quickSelectTimeout = 600 * time.Millisecond
+
+// quickSelectMsg is sent when the quick-select digit buffer times out. seq
+// identifies the keypress that scheduled it so stale timeouts are ignored.
+type quickSelectMsg struct {
+	seq int
+}
+
 // Model represents the Bubble Tea model
 type Model struct {
 	habitManager   *internal.HabitManager
@@ -197,6 +266,15 @@ type Model struct {
 	help           help.Model
 	keys           keyMap
 	showHelp       bool
+	viewport       viewport.Model
+	digitBuffer    string
+	digitSeq       int
+	profile        string
+	profiles       []internal.Profile
+	profileList    list.Model
+	profileErr     string
+	authors        []string
+	colorByPerson  bool
 }
 
 // NewModel creates a new TUI model with default timeline
@@ -217,54 +295,89 @@ func NewModelWithOptions(timeline TimelineDays, showLegend bool) *Model {
 		os.Exit(1)
 	}
 
-	activities := hm.GetActivities()
-	grid := generateGrid(activities, timeline)
 	renderingLevel := detectRenderingLevel()
-	
-	// Create sorted activity keys
-	activityKeys := make([]string, 0, len(activities))
-	for key := range activities {
-		activityKeys = append(activityKeys, key)
-	}
-	sort.Strings(activityKeys)
-
-	// Create list items for bubbles/list
-	items := make([]list.Item, 0, len(activityKeys))
-	for _, key := range activityKeys {
-		items = append(items, HabitItem{
-			key:      key,
-			activity: activities[key],
-		})
-	}
 
 	// Configure the list
-	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
 	l.Title = "Select a Habit"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
 
+	pl := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	pl.Title = "Switch Profile"
+	pl.SetShowStatusBar(false)
+	pl.SetFilteringEnabled(false)
+	pl.Styles.Title = l.Styles.Title
+
 	// Configure help
 	h := help.New()
 	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	h.Styles.ShortDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+
+	// Page through the all-activities view; up/down move the highlighted
+	// habit instead, and the remaining viewport defaults clash with
+	// existing bindings
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{
+		PageUp:   keys.PageUp,
+		PageDown: keys.PageDown,
+	}
 	
-	return &Model{
-		habitManager:   hm,
-		activities:     activities,
-		grid:           grid,
+	m := &Model{
 		ready:          true,
 		renderingLevel: renderingLevel,
 		viewMode:       AllActivities,
-		activityKeys:   activityKeys,
-		selectedIndex:  0,
 		timeline:       timeline,
 		showLegend:     showLegend,
 		habitList:      l,
 		help:           h,
 		keys:           keys,
 		showHelp:       false,
+		viewport:       vp,
+		profileList:    pl,
+	}
+	m.setHabitManager(hm)
+	return m
+}
+
+// setHabitManager shows the habits of a loaded manager, resetting the
+// selection; used at startup and when switching profile
+func (m *Model) setHabitManager(hm *internal.HabitManager) {
+	m.habitManager = hm
+	m.activities = hm.GetActivities()
+	m.activityKeys = hm.SortedKeys()
+	m.authors = internal.Authors(m.activities)
+	m.selectedIndex = 0
+	m.grid = generateGrid(m.activities, m.timeline)
+	m.updateListItems()
+	m.viewport.SetYOffset(0)
+
+	m.profile = internal.ActiveProfile()
+	m.profiles, _ = internal.ListProfiles()
+	items := make([]list.Item, 0, len(m.profiles))
+	for i, profile := range m.profiles {
+		items = append(items, ProfileItem{profile: profile, active: profile.Name == m.profile})
+		if profile.Name == m.profile {
+			m.profileList.Select(i)
+		}
 	}
+	m.profileList.SetItems(items)
+}
+
+// switchProfile loads another profile's habits, staying on the current
+// profile if they can't be loaded
+func (m *Model) switchProfile(name string) {
+	previous := m.profile
+	internal.UseProfile(name)
+	hm := internal.NewHabitManager()
+	if err := hm.Load(); err != nil {
+		internal.UseProfile(previous)
+		m.profileErr = fmt.Sprintf("Error loading profile '%s': %v", name, err)
+		return
+	}
+	m.profileErr = ""
+	m.setHabitManager(hm)
 }
 
 // Detect terminal rendering capabilities
@@ -393,7 +506,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case quickSelectMsg:
+		// Only the most recent digit's timeout commits the selection
+		if msg.seq == m.digitSeq && m.digitBuffer != "" {
+			m.commitQuickSelect()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		// While the habit filter is being typed, every key belongs to the list;
+		// enter both accepts the filter and opens the top match
+		if m.viewMode == HabitSelection && m.habitList.FilterState() == list.Filtering {
+			m.habitList, cmd = m.habitList.Update(msg)
+			if key.Matches(msg, m.keys.Enter) {
+				m.selectListItem()
+			}
+			return m, cmd
+		}
+
 		// Handle help toggle first
 		if key.Matches(msg, m.keys.Help) {
 			m.showHelp = !m.showHelp
@@ -405,24 +535,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		// Open the profile switcher from the grid views
+		if key.Matches(msg, m.keys.Profiles) && (m.viewMode == AllActivities || m.viewMode == SingleActivity) {
+			m.digitBuffer = ""
+			m.viewMode = ProfileSelection
+			return m, nil
+		}
+
 		// Handle view-specific keys
 		switch m.viewMode {
+		case ProfileSelection:
+			m.profileList, cmd = m.profileList.Update(msg)
+
+			if key.Matches(msg, m.keys.Enter) {
+				if item, ok := m.profileList.SelectedItem().(ProfileItem); ok && item.profile.Name != m.profile {
+					m.switchProfile(item.profile.Name)
+				}
+				m.viewMode = AllActivities
+			}
+			if key.Matches(msg, m.keys.Escape) {
+				m.viewMode = AllActivities
+			}
+
 		case HabitSelection:
 			// Update list
 			m.habitList, cmd = m.habitList.Update(msg)
 			
 			// Handle selection
 			if key.Matches(msg, m.keys.Enter) {
-				if selectedItem, ok := m.habitList.SelectedItem().(HabitItem); ok {
-					// Find the index of the selected habit
-					for i, key := range m.activityKeys {
-						if key == selectedItem.key {
-							m.selectedIndex = i
-							break
-						}
-					}
-					m.viewMode = SingleActivity
-				}
+				m.selectListItem()
 			}
 			
 			// Handle escape to go back
@@ -435,15 +576,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key.Matches(msg, m.keys.Tab) {
 				m.viewMode = HabitSelection
 			}
+
+			// Handle jump to open the habit list straight into filtering
+			if key.Matches(msg, m.keys.Jump) {
+				m.digitBuffer = ""
+				m.viewMode = HabitSelection
+				m.habitList.ResetFilter()
+				m.habitList, cmd = m.habitList.Update(msg)
+				return m, cmd
+			}
 			
-			// Handle number keys for direct selection
+			// Handle number keys for direct selection; digits are buffered so
+			// habits past the ninth can be reached
 			switch msg.String() {
-			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
-				num := int(msg.String()[0] - '1')
-				if num >= 0 && num < len(m.activityKeys) {
-					m.selectedIndex = num
+			case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				cmd = m.bufferDigit(msg.String())
+				return m, cmd
+			}
+
+			// Enter selects whatever has been typed without waiting,
+			// otherwise it opens the highlighted habit
+			if key.Matches(msg, m.keys.Enter) {
+				if m.digitBuffer != "" {
+					m.commitQuickSelect()
+				} else if len(m.activityKeys) > 0 {
 					m.viewMode = SingleActivity
 				}
+				return m, nil
+			}
+			m.digitBuffer = ""
+
+			// Move the highlight, keeping it in view
+			if key.Matches(msg, m.keys.Up) && m.selectedIndex > 0 {
+				m.selectedIndex--
+				m.syncViewport()
+				m.scrollToSelected()
+			}
+			if key.Matches(msg, m.keys.Down) && m.selectedIndex < len(m.activityKeys)-1 {
+				m.selectedIndex++
+				m.syncViewport()
+				m.scrollToSelected()
+			}
+
+			// Page through the grids when they don't fit on screen
+			if key.Matches(msg, m.keys.PageUp, m.keys.PageDown) {
+				m.syncViewport()
+				m.viewport, cmd = m.viewport.Update(msg)
 			}
 
 		case SingleActivity:
@@ -459,10 +637,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key.Matches(msg, m.keys.Tab) {
 				m.viewMode = HabitSelection
 			}
+
+			// Handle jump to open the habit list straight into filtering
+			if key.Matches(msg, m.keys.Jump) {
+				m.viewMode = HabitSelection
+				m.habitList.ResetFilter()
+				m.habitList, cmd = m.habitList.Update(msg)
+				return m, cmd
+			}
 			
 			// Handle all activities view
 			if key.Matches(msg, m.keys.AllView) {
 				m.viewMode = AllActivities
+				m.syncViewport()
+				m.scrollToSelected()
 			}
 			
 			// Handle logging activity
@@ -495,17 +683,92 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if key.Matches(msg, m.keys.ToggleLegend) {
 			m.showLegend = !m.showLegend
 		}
+		if key.Matches(msg, m.keys.ByPerson) && m.viewMode != HabitSelection {
+			m.colorByPerson = !m.colorByPerson
+		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.habitList.SetWidth(msg.Width)
 		m.habitList.SetHeight(msg.Height - 4) // Leave space for help
+		m.profileList.SetWidth(msg.Width)
+		m.profileList.SetHeight(msg.Height - 4)
 		return m, nil
+
+	case tea.MouseMsg:
+		// Scroll the all-activities view with the mouse wheel
+		if m.viewMode == AllActivities {
+			m.syncViewport()
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+
+	default:
+		// Pass through the list's own messages, such as filter results
+		m.habitList, cmd = m.habitList.Update(msg)
 	}
 	return m, cmd
 }
 
+// selectListItem opens the habit currently highlighted in the habit list
+func (m *Model) selectListItem() {
+	if selectedItem, ok := m.habitList.SelectedItem().(HabitItem); ok {
+		// Find the index of the selected habit
+		for i, key := range m.activityKeys {
+			if key == selectedItem.key {
+				m.selectedIndex = i
+				break
+			}
+		}
+		m.viewMode = SingleActivity
+	}
+}
+
+// scrollToSelected scrolls the all-activities viewport just far enough to
+// show the highlighted habit's whole grid
+func (m *Model) scrollToSelected() {
+	top := m.selectedIndex * activityBlockHeight
+	bottom := top + activityGridHeight
+	switch {
+	case top < m.viewport.YOffset:
+		m.viewport.SetYOffset(top)
+	case bottom > m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(bottom - m.viewport.Height)
+	}
+}
+
+// bufferDigit appends a quick-select digit and either selects the habit right
+// away, when no further digit could name another habit, or waits for more
+func (m *Model) bufferDigit(digit string) tea.Cmd {
+	if m.digitBuffer == "" && digit == "0" {
+		return nil
+	}
+
+	m.digitBuffer += digit
+	m.digitSeq++
+
+	num, _ := strconv.Atoi(m.digitBuffer)
+	if num*10 > len(m.activityKeys) {
+		m.commitQuickSelect()
+		return nil
+	}
+
+	seq := m.digitSeq
+	return tea.Tick(quickSelectTimeout, func(time.Time) tea.Msg {
+		return quickSelectMsg{seq: seq}
+	})
+}
+
+// commitQuickSelect opens the habit named by the digit buffer, if any
+func (m *Model) commitQuickSelect() {
+	num, err := strconv.Atoi(m.digitBuffer)
+	m.digitBuffer = ""
+	if err == nil && num >= 1 && num <= len(m.activityKeys) {
+		m.selectedIndex = num - 1
+		m.viewMode = SingleActivity
+	}
+}
+
 // updateListItems refreshes the list items with current activity data
 func (m *Model) updateListItems() {
 	items := make([]list.Item, 0, len(m.activityKeys))
@@ -538,6 +801,45 @@ func (m Model) View() string {
 		return s.String()
 	}
 
+	// Handle profile selection view
+	if m.viewMode == ProfileSelection {
+		var s strings.Builder
+		s.WriteString(m.profileList.View())
+		s.WriteString("\n")
+		s.WriteString(m.help.ShortHelpView([]key.Binding{m.keys.Enter, m.keys.Escape, m.keys.Quit}))
+		return s.String()
+	}
+
+	var s strings.Builder
+	s.WriteString(m.headerView())
+
+	// Render activities based on view mode
+	if m.viewMode == AllActivities {
+		// Show all activities, scrolled once the window size is known
+		if m.height > 0 {
+			m.syncViewport()
+			s.WriteString(m.viewport.View())
+		} else {
+			s.WriteString(m.allActivitiesView())
+		}
+	} else if m.viewMode == SingleActivity {
+		// Show single selected activity
+		if len(m.activityKeys) > 0 {
+			key := m.activityKeys[m.selectedIndex]
+			activity := m.activities[key]
+			s.WriteString(m.renderActivityGrid(activity, key, -1)) // -1 means no number
+			s.WriteString(m.trendsView(key, activity))
+			s.WriteString(m.goalsView(key, activity))
+		}
+	}
+
+	s.WriteString(m.footerView())
+
+	return s.String()
+}
+
+// headerView renders the title block shown above the grids
+func (m Model) headerView() string {
 	var s strings.Builder
 	
 	// Title
@@ -547,28 +849,32 @@ func (m Model) View() string {
 		Margin(1, 0)
 	
 	// Title changes based on view mode and timeline
-	var timelineText string
-	switch m.timeline {
-	case Timeline3Months:
-		timelineText = "3 months"
-	case Timeline6Months:
-		timelineText = "6 months"
-	case Timeline12Months:
-		timelineText = "12 months"
-	}
+	timelineText := timelineLabel(m.timeline)
 	
+	// Name the profile once there's more than one to tell apart
+	appName := "Activity Tracker"
+	if len(m.profiles) > 1 {
+		appName = fmt.Sprintf("Activity Tracker [%s]", m.profile)
+	}
+
 	var titleText string
 	if m.viewMode == AllActivities {
-		titleText = fmt.Sprintf("Activity Tracker - All Activities (%s)", timelineText)
+		titleText = fmt.Sprintf("%s - All Activities (%s)", appName, timelineText)
 	} else {
 		if len(m.activityKeys) > 0 {
 			selectedActivity := m.activities[m.activityKeys[m.selectedIndex]]
-			titleText = fmt.Sprintf("Activity Tracker - %s (%s)", selectedActivity.Name, timelineText)
+			titleText = fmt.Sprintf("%s - %s (%s)", appName, selectedActivity.Name, timelineText)
 		} else {
-			titleText = fmt.Sprintf("Activity Tracker (%s)", timelineText)
+			titleText = fmt.Sprintf("%s (%s)", appName, timelineText)
 		}
 	}
 	s.WriteString(titleStyle.Render(titleText))
+
+	if m.profileErr != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render(m.profileErr))
+	}
 	
 	// Show rendering mode in debug mode
 	if os.Getenv("HAB_DEBUG") == "true" {
@@ -588,24 +894,25 @@ func (m Model) View() string {
 	
 	s.WriteString("\n\n")
 
-	// Render activities based on view mode
-	if m.viewMode == AllActivities {
-		// Show all activities
-		for i, key := range m.activityKeys {
-			activity := m.activities[key]
-			s.WriteString(m.renderActivityGrid(activity, key, i+1))
-			if i < len(m.activityKeys)-1 {
-				s.WriteString("\n\n")
-			}
-		}
-	} else if m.viewMode == SingleActivity {
-		// Show single selected activity
-		if len(m.activityKeys) > 0 {
-			key := m.activityKeys[m.selectedIndex]
-			activity := m.activities[key]
-			s.WriteString(m.renderActivityGrid(activity, key, -1)) // -1 means no number
+	return s.String()
+}
+
+// allActivitiesView renders every habit grid, numbered for quick-select
+func (m Model) allActivitiesView() string {
+	var s strings.Builder
+	for i, key := range m.activityKeys {
+		activity := m.activities[key]
+		s.WriteString(m.renderActivityGridFocused(activity, key, i+1, i == m.selectedIndex))
+		if i < len(m.activityKeys)-1 {
+			s.WriteString("\n\n")
 		}
 	}
+	return s.String()
+}
+
+// footerView renders the legend and help shown below the grids
+func (m Model) footerView() string {
+	var s strings.Builder
 
 	// Legend (right-aligned to grid end) - only show if enabled
 	if m.showLegend && m.viewMode != HabitSelection {
@@ -631,6 +938,19 @@ func (m Model) View() string {
 			s.WriteString(padding)
 		}
 		s.WriteString(legendStyle.Render(legendText))
+
+		// Name the people colors when cells are colored by person
+		if m.colorByPerson && len(m.authors) > 0 {
+			s.WriteString("\n")
+			s.WriteString(strings.Repeat(" ", 3))
+			for i, person := range m.authors {
+				if i > 0 {
+					s.WriteString("  ")
+				}
+				personStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.personColor(person)))
+				s.WriteString(personStyle.Render(charSet.Complete + " " + person))
+			}
+		}
 	}
 
 	// Footer with help/controls
@@ -641,36 +961,142 @@ func (m Model) View() string {
 		// Show appropriate short help based on view mode
 		var helpKeys []key.Binding
 		if m.viewMode == AllActivities {
-			helpKeys = []key.Binding{m.keys.Tab, m.keys.Timeline3m, m.keys.ToggleLegend, m.keys.Help, m.keys.Quit}
+			helpKeys = []key.Binding{m.keys.Up, m.keys.Enter, m.keys.PageDown, m.keys.Tab, m.keys.Jump, m.keys.ToggleLegend, m.keys.Help, m.keys.Quit}
 		} else {
 			helpKeys = []key.Binding{m.keys.Up, m.keys.Enter, m.keys.AllView, m.keys.Tab, m.keys.Help, m.keys.Quit}
 		}
+		if len(m.profiles) > 1 {
+			helpKeys = append(helpKeys[:len(helpKeys)-2], m.keys.Profiles, m.keys.Help, m.keys.Quit)
+		}
 		s.WriteString(m.help.ShortHelpView(helpKeys))
 	}
 
+	// Show the pending quick-select number while more digits may follow
+	if m.digitBuffer != "" {
+		pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+		s.WriteString("  ")
+		s.WriteString(pendingStyle.Render(fmt.Sprintf("go to [%s_]", m.digitBuffer)))
+	}
+
+	return s.String()
+}
+
+// goalProgressWidth is the width of a goal's progress bar in cells
+const goalProgressWidth = 30
+
+// trendDays is how many days of trends the sparklines show
+const trendDays = 30
+
+// trendsView renders sparklines of a habit's strength and rolling
+// completion rates over the last month
+func (m Model) trendsView(activityKey string, activity internal.Activity) string {
+	trends := m.habitManager.Trends(activityKey, time.Now())
+	if trends.Len() == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(getColorCode(activity.Color)))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	last := trends.Len() - 1
+
+	s.WriteString("\n")
+	for _, row := range []struct {
+		label  string
+		series []float64
+	}{
+		{"Strength", trends.Strength},
+		{"7-day", trends.Rolling7},
+		{"30-day", trends.Rolling30},
+	} {
+		s.WriteString(fmt.Sprintf("   %-9s %3.0f%%  ", row.label, row.series[last]*100))
+		s.WriteString(lineStyle.Render(sparkline(row.series, trendDays, m.renderingLevel)))
+		s.WriteString("\n")
+	}
+	s.WriteString("   ")
+	s.WriteString(dimStyle.Render(fmt.Sprintf("last %d days", min(trends.Len(), trendDays))))
+	s.WriteString("\n")
+	return s.String()
+}
+
+// goalsView renders a progress bar for each of a habit's active goals
+func (m Model) goalsView(activityKey string, activity internal.Activity) string {
+	var s strings.Builder
+	charSet := characterSets[m.renderingLevel]
+	barStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(getColorCode(activity.Color)))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	now := time.Now()
+	for _, id := range m.habitManager.HabitGoalIDs(activityKey) {
+		progress, err := m.habitManager.GoalProgress(id, now)
+		if err != nil || progress.Status != internal.GoalActive {
+			continue
+		}
+
+		filled := int(progress.Percent() * goalProgressWidth)
+		s.WriteString("\n   ")
+		s.WriteString(barStyle.Render(strings.Repeat(charSet.Complete, filled)))
+		s.WriteString(dimStyle.Render(strings.Repeat(charSet.None, goalProgressWidth-filled)))
+		s.WriteString(fmt.Sprintf(" %3.0f%%  %s: %s\n", progress.Percent()*100, id, progress.Goal.Describe()))
+
+		details := fmt.Sprintf("%d/%d done, %s, %d days left", progress.Done, progress.Goal.Target,
+			progress.StatusText(), progress.DaysLeft)
+		if progress.Goal.Kind == internal.GoalTotal {
+			details += fmt.Sprintf(", %.2f/day needed", progress.RequiredPace)
+		}
+		if !progress.Projected.IsZero() {
+			details += ", on pace for " + progress.Projected.Format("Jan 2, 2006")
+		}
+		s.WriteString("   ")
+		s.WriteString(dimStyle.Render(details))
+		s.WriteString("\n")
+	}
 	return s.String()
 }
 
+// syncViewport refreshes the all-activities viewport with the current grids
+// and fits it into the space left between the header and footer
+func (m *Model) syncViewport() {
+	m.viewport.Width = m.width
+	// The header's trailing newline and the footer's leading one share lines
+	// with the viewport, hence the two lines handed back
+	m.viewport.Height = max(1, m.height-lipgloss.Height(m.headerView())-lipgloss.Height(m.footerView())+2)
+	m.viewport.SetContent(m.allActivitiesView())
+}
+
 // Render a single activity grid
 func (m Model) renderActivityGrid(activity internal.Activity, activityKey string, activityNumber int) string {
+	return m.renderActivityGridFocused(activity, activityKey, activityNumber, false)
+}
+
+// Render a single activity grid, highlighting its title when focused
+func (m Model) renderActivityGridFocused(activity internal.Activity, activityKey string, activityNumber int, focused bool) string {
 	var s strings.Builder
 	
 	// Activity title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color(getColorCode(activity.Color)))
+	if focused {
+		titleStyle = titleStyle.Reverse(true)
+	}
 	
 	totalDates := len(activity.Dates)
+	unit := "activities"
+	if activity.IsAvoid() {
+		unit = "slips"
+	}
 	var titleText string
 	if activityNumber > 0 {
-		titleText = fmt.Sprintf("[%d] %s (%d activities)", activityNumber, activity.Name, totalDates)
+		titleText = fmt.Sprintf("[%d] %s (%d %s)", activityNumber, activity.Name, totalDates, unit)
 	} else {
-		titleText = fmt.Sprintf("%s (%d activities)", activity.Name, totalDates)
+		titleText = fmt.Sprintf("%s (%d %s)", activity.Name, totalDates, unit)
 	}
 	s.WriteString(titleStyle.Render(titleText))
 	s.WriteString("\n")
 
 	// Render grid rows (7 days per row)  
+	tracked := activity.Track(time.Now())
 	dayLabels := []string{"S", "M", "T", "W", "T", "F", "S"}
 	for row := 0; row < 7; row++ {
 		s.WriteString(fmt.Sprintf("%-3s", dayLabels[row]))
@@ -678,8 +1104,8 @@ func (m Model) renderActivityGrid(activity internal.Activity, activityKey string
 		for week := 0; week < len(m.grid); week++ {
 			if week < len(m.grid) && row < len(m.grid[week]) {
 				cell := m.grid[week][row]
-				char := m.getCellChar(cell, activity, activityKey)
-				color := m.getCellColor(cell, activity, activityKey)
+				char := m.getCellChar(cell, tracked, activityKey)
+				color := m.getCellColor(cell, tracked, activityKey)
 				
 				cellStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
 				s.WriteString(cellStyle.Render(char))
@@ -693,19 +1119,38 @@ func (m Model) renderActivityGrid(activity internal.Activity, activityKey string
 }
 
 // Get character for cell based on activity level
-func (m Model) getCellChar(cell ContributionGrid, activity internal.Activity, activityKey string) string {
-	dateStr := cell.Date.Format("2006-01-02")
+func (m Model) getCellChar(cell ContributionGrid, tracked internal.TrackedDays, activityKey string) string {
+	// Get the appropriate character set for this terminal
+	charSet := characterSets[m.renderingLevel]
 	
-	// Count how many times this activity was completed on this date
-	completions := 0
-	for _, activityDate := range activity.Dates {
-		if activityDate == dateStr {
-			completions++
-		}
+	// Return character based on completion rate
+	date := cell.Date.Format("2006-01-02")
+	switch dayLevel(tracked, m.habitManager.CountOn(activityKey, date), date) {
+	case LevelNone:
+		return charSet.None // No activity
+	case LevelLow:
+		return charSet.Low // Low completion (< 50%)
+	case LevelPartial:
+		return charSet.Partial // Partial completion (50-99%)
+	default:
+		return charSet.Complete // Target met or exceeded
 	}
-	
+}
+
+// CompletionLevel buckets how much of its daily target a habit met
+type CompletionLevel int
+
+const (
+	LevelNone     CompletionLevel = iota // 0% complete
+	LevelLow                             // < 50% complete
+	LevelPartial                         // 50-99% complete
+	LevelComplete                        // 100%+ complete
+)
+
+// completionLevel returns how much of its daily target a count of entries meets
+func completionLevel(completions, targetPerDay int) CompletionLevel {
 	// Default target is 1 if not specified
-	target := activity.TargetPerDay
+	target := targetPerDay
 	if target == 0 {
 		target = 1
 	}
@@ -713,33 +1158,75 @@ func (m Model) getCellChar(cell ContributionGrid, activity internal.Activity, ac
 	// Calculate completion percentage
 	completionRate := float64(completions) / float64(target)
 	
-	// Get the appropriate character set for this terminal
-	charSet := characterSets[m.renderingLevel]
-	
-	// Return character based on completion rate
 	switch {
 	case completionRate == 0:
-		return charSet.None // No activity
+		return LevelNone
 	case completionRate < 0.5:
-		return charSet.Low // Low completion (< 50%)
+		return LevelLow
 	case completionRate < 1.0:
-		return charSet.Partial // Partial completion (50-99%)
+		return LevelPartial
 	default:
-		return charSet.Complete // Target met or exceeded
+		return LevelComplete
 	}
 }
 
+// dayLevel grades a day of a habit. A habit to avoid is complete on clean
+// days and has nothing on days with slips or outside its tracked period.
+func dayLevel(tracked internal.TrackedDays, completions int, date string) CompletionLevel {
+	if tracked.Activity.IsAvoid() {
+		if tracked.IsCleanDay(date, completions) {
+			return LevelComplete
+		}
+		return LevelNone
+	}
+	return completionLevel(completions, tracked.Activity.TargetPerDay)
+}
+
 // Get color for cell based on activity
-func (m Model) getCellColor(cell ContributionGrid, activity internal.Activity, activityKey string) string {
-	dateStr := cell.Date.Format("2006-01-02")
-	for _, activityDate := range activity.Dates {
-		if activityDate == dateStr {
-			return getColorCode(activity.Color)
+func (m Model) getCellColor(cell ContributionGrid, tracked internal.TrackedDays, activityKey string) string {
+	activity := tracked.Activity
+	date := cell.Date.Format("2006-01-02")
+	if activity.IsAvoid() {
+		return m.avoidCellColor(tracked, activityKey, date)
+	}
+	if m.habitManager.CountOn(activityKey, date) > 0 {
+		// Shared habits can show who logged most of each day's entries
+		if m.colorByPerson {
+			if person := internal.TopContributor(activity, date); person != "" {
+				return m.personColor(person)
+			}
 		}
+		return getColorCode(activity.Color)
 	}
 	return "8" // Dim gray for inactive
 }
 
+// avoidCellColor colors a day of a habit to avoid: clean days take the
+// habit's color and slips stand out in red, or the color of who slipped
+func (m Model) avoidCellColor(tracked internal.TrackedDays, activityKey, date string) string {
+	activity := tracked.Activity
+	count := m.habitManager.CountOn(activityKey, date)
+	switch {
+	case tracked.IsCleanDay(date, count):
+		return getColorCode(activity.Color)
+	case count == 0:
+		return "8" // Dim gray outside the tracked period
+	case m.colorByPerson && internal.TopContributor(activity, date) != "":
+		return m.personColor(internal.TopContributor(activity, date))
+	default:
+		return "1"
+	}
+}
+
+// personColor returns the color of a person who logs shared habits
+func (m Model) personColor(person string) string {
+	i := slices.Index(m.authors, person)
+	if i < 0 {
+		return "7"
+	}
+	return personColors[i%len(personColors)]
+}
+
 // Convert color names to terminal color codes
 func getColorCode(colorName string) string {
 	colorMap := map[string]string{
@@ -769,7 +1256,7 @@ func RunTUIWithTimeline(timeline TimelineDays) {
 
 func RunTUIWithOptions(timeline TimelineDays, showLegend bool) {
 	m := NewModelWithOptions(timeline, showLegend)
-	p := tea.NewProgram(m)
+	p := tea.NewProgram(m, tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running TUI: %v\n", err)
 		os.Exit(1)
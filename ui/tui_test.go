@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hab/internal"
+)
+
+// benchmarkModel opens the TUI model on a data file holding a few thousand
+// entries over the last few years, for a habit to build and one to avoid
+func benchmarkModel(b *testing.B) *Model {
+	b.Helper()
+	dir := b.TempDir()
+	b.Setenv("HAB_DATA_FILE", filepath.Join(dir, "activities.json"))
+	b.Setenv("HAB_CONFIG_FILE", filepath.Join(dir, "config.json"))
+	b.Setenv("HAB_STORAGE", "")
+	b.Setenv("HAB_PROFILE", "")
+
+	today := time.Now()
+	var entries, slips []string
+	for i := 0; i < 1200; i++ {
+		date := today.AddDate(0, 0, -i).Format(internal.DateFormat)
+		entries = append(entries, date, date, date)
+		if i%5 == 0 {
+			slips = append(slips, date)
+		}
+	}
+
+	hm := internal.NewHabitManager()
+	err := hm.Replace(&internal.ActivitiesData{
+		Version: internal.DataVersion,
+		Activities: map[string]internal.Activity{
+			"exercise": {Name: "Exercise", Color: "green", Dates: entries, TargetPerDay: 2},
+			"nosugar":  {Name: "No sugar", Color: "red", Dates: slips, Polarity: internal.PolarityAvoid},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return NewModelWithOptions(Timeline12Months, true)
+}
+
+func BenchmarkRenderActivityGrid(b *testing.B) {
+	m := benchmarkModel(b)
+	for _, key := range m.activityKeys {
+		b.Run(key, func(b *testing.B) {
+			activity := m.activities[key]
+			for i := 0; i < b.N; i++ {
+				m.renderActivityGrid(activity, key, 1)
+			}
+		})
+	}
+}